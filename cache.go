@@ -2,7 +2,6 @@ package mapper
 
 import (
 	"reflect"
-	"sync"
 )
 
 type fieldMeta struct {
@@ -11,6 +10,18 @@ type fieldMeta struct {
 	Type      reflect.Type
 	Tag       string
 	ConvertTo string
+	// NoStrictSource is set by the `mapstrict:"-"` tag and exempts the field
+	// from the WithStrictSource check.
+	NoStrictSource bool
+	// Merge is the strategy set by the mapmerge and mapkey tags.
+	Merge       mergeSpec
+	MergeTagged bool
+	// Pointers is set by the mapptr tag.
+	Pointers pointerMode
+	// TagError describes a malformed tag and is reported with TagErrorCode
+	// when the field is mapped.
+	TagError     string
+	TagErrorCode ErrorCode
 }
 
 type structMeta struct {
@@ -26,27 +37,26 @@ type cacheKey struct {
 	tagName string
 }
 
-var structMetaCache sync.Map // map[cacheKey]*structMeta
-
-func getStructMeta(t reflect.Type, tagName string) (*structMeta, error) {
+func (m *Mapper) getStructMeta(t reflect.Type, tagName string) (*structMeta, error) {
 	if t.Kind() != reflect.Struct {
 		return nil, &MappingError{
 			SrcType:   "",
 			DstType:   "",
 			FieldPath: "",
 			Reason:    "type is not a struct",
+			Code:      CodeInvalidInput,
 		}
 	}
 
 	key := cacheKey{typ: t, tagName: tagName}
 
-	if cached, ok := structMetaCache.Load(key); ok {
+	if cached, ok := m.metaCache.Load(key); ok {
 		return cached.(*structMeta), nil
 	}
 
-	m := buildStructMeta(t, tagName)
+	meta := buildStructMeta(t, tagName)
 
-	actual, _ := structMetaCache.LoadOrStore(key, m)
+	actual, _ := m.metaCache.LoadOrStore(key, meta)
 	return actual.(*structMeta), nil
 }
 
@@ -69,7 +79,7 @@ func buildStructMeta(t reflect.Type, tagName string) *structMeta {
 		}
 
 		switch sf.Type.Kind() {
-		case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Struct:
+		case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Struct, reflect.Interface:
 			m.HasComposite = true
 		}
 
@@ -83,6 +93,32 @@ func buildStructMeta(t reflect.Type, tagName string) *structMeta {
 			meta.ConvertTo = convTag
 		}
 
+		if sf.Tag.Get("mapstrict") == "-" {
+			meta.NoStrictSource = true
+		}
+
+		mergeTag, hasMerge := sf.Tag.Lookup("mapmerge")
+		keyTag, hasKey := sf.Tag.Lookup("mapkey")
+		if hasMerge || hasKey {
+			meta.MergeTagged = true
+			meta.Merge, meta.TagError = parseMergeTags(mergeTag, keyTag, hasKey, sf.Type)
+			if meta.TagError != "" {
+				meta.TagErrorCode = CodeInvalidMerge
+			}
+		}
+
+		if ptrTag, ok := sf.Tag.Lookup("mapptr"); ok && meta.TagError == "" {
+			switch ptrTag {
+			case "reuse":
+				meta.Pointers = pointerReuse
+			case "new":
+				meta.Pointers = pointerAlloc
+			default:
+				meta.TagError = "unknown mapptr value: " + ptrTag
+				meta.TagErrorCode = CodeInvalidInput
+			}
+		}
+
 		m.Fields = append(m.Fields, meta)
 		m.FieldsByName[sf.Name] = meta
 
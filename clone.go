@@ -0,0 +1,177 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// Clone returns a deep copy of v, which may be of any type: a struct, slice,
+// map, pointer, array or a value held in an interface.
+//
+// Pointers, slices and maps are copied recursively, as are the values of
+// arrays, interfaces and the exported fields of structs. Unlike [Map], no
+// field matching is involved and the top level need not be a struct. Nil
+// pointers, slices and maps stay nil and empty slices and maps stay empty.
+// Unexported struct fields, channels and functions are copied shallowly, and
+// map keys are reused as they are.
+//
+// Example:
+//
+//	snapshot, err := mapper.Clone(order)
+//	byID, err := mapper.Clone(usersByID) // map[string]*User
+//
+// Converters registered for a type to itself, with [RegisterConverter] or
+// [WithConverter], are used to copy values of that type. With
+// [WithPreserveReferences], a pointer reached more than once is copied once,
+// so shared objects stay shared and cyclic values can be cloned; otherwise
+// cycles fail once [WithMaxDepth] is exceeded. Errors are returned as a
+// [*MappingError] with the path of the failing value, such as
+// "Items[2].Next".
+func Clone[T any](v T, opts ...Option) (T, error) {
+	cfg := defaultMapper.callConfig(opts)
+
+	var out T
+	outVal := reflect.ValueOf(&out).Elem()
+	src := reflect.ValueOf(&v).Elem()
+
+	copied, err := cloneDeep(src, outVal.Type(), &cfg, cfg.maxDepth)
+	if err != nil {
+		return out, err
+	}
+	outVal.Set(copied)
+	return out, nil
+}
+
+// cloneDeep returns a deep copy of v; see [Clone]. rootType is reported in
+// errors, whose paths are relative to v.
+func cloneDeep(v reflect.Value, rootType reflect.Type, cfg *config, depth int) (reflect.Value, error) {
+	t := v.Type()
+	out := reflect.New(t).Elem()
+
+	if depth <= 0 {
+		return out, &MappingError{
+			SrcType:   rootType.String(),
+			DstType:   rootType.String(),
+			FieldPath: "",
+			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
+		}
+	}
+
+	if cfg.hasConverters() {
+		if conv, ok := cfg.lookupConverter(t, t); ok {
+			return out, applyConverter(conv, out, v, rootType, rootType, "", cfg)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return out, nil
+		}
+		if ref, ok := cfg.mappedRef(v, t); ok {
+			return ref, nil
+		}
+		ptr := reflect.New(t.Elem())
+		cfg.rememberRef(v, ptr)
+		elem, err := cloneDeep(v.Elem(), rootType, cfg, depth-1)
+		if err != nil {
+			return out, err
+		}
+		ptr.Elem().Set(elem)
+		out.Set(ptr)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return out, nil
+		}
+		elem, err := cloneDeep(v.Elem(), rootType, cfg, depth-1)
+		if err != nil {
+			return out, err
+		}
+		out.Set(elem)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return out, nil
+		}
+		out.Set(reflect.MakeSlice(t, v.Len(), v.Len()))
+		if err := cloneElements(out, v, rootType, cfg, depth-1); err != nil {
+			return out, err
+		}
+
+	case reflect.Array:
+		out.Set(v)
+		if err := cloneElements(out, v, rootType, cfg, depth-1); err != nil {
+			return out, err
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return out, nil
+		}
+		out.Set(reflect.MakeMapWithSize(t, v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, err := cloneDeep(iter.Value(), rootType, cfg, depth-1)
+			if err != nil {
+				return out, prependMapKeyPath(err, "", iter.Key())
+			}
+			out.SetMapIndex(iter.Key(), elem)
+		}
+
+	case reflect.Struct:
+		out.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			field := out.Field(i)
+			if !field.CanSet() || !needsClone(field.Kind(), cfg) {
+				continue
+			}
+			elem, err := cloneDeep(v.Field(i), rootType, cfg, depth-1)
+			if err != nil {
+				return out, prependFieldPath(err, t.Field(i).Name)
+			}
+			field.Set(elem)
+		}
+
+	default:
+		out.Set(v)
+	}
+
+	return out, nil
+}
+
+// cloneElements deep copies the elements of the slice or array src into dst.
+func cloneElements(dst, src reflect.Value, rootType reflect.Type, cfg *config, depth int) error {
+	if !needsClone(src.Type().Elem().Kind(), cfg) {
+		reflect.Copy(dst, src)
+		return nil
+	}
+	for i := 0; i < src.Len(); i++ {
+		elem, err := cloneDeep(src.Index(i), rootType, cfg, depth)
+		if err != nil {
+			return prependIndexPath(err, "", i)
+		}
+		dst.Index(i).Set(elem)
+	}
+	return nil
+}
+
+// needsClone reports whether values of kind k must be copied recursively
+// rather than assigned.
+func needsClone(k reflect.Kind, cfg *config) bool {
+	switch k {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return true
+	default:
+		// Converters may target any type, including scalars
+		return cfg.hasConverters()
+	}
+}
+
+// prependFieldPath prepends a field name to a MappingError's FieldPath.
+func prependFieldPath(err error, name string) error {
+	if e, ok := err.(*MappingError); ok {
+		e.FieldPath = joinPath(name, e.FieldPath)
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type cloneLine struct {
+	SKU   string
+	Attrs map[string][]int
+}
+
+type cloneOrder struct {
+	ID      int
+	Lines   []cloneLine
+	Notes   []string
+	Empty   []string
+	Meta    map[string]any
+	Owner   *cloneOwner
+	Backup  *cloneOwner
+	Fixed   [2]*int
+	Created time.Time
+	secret  *int
+}
+
+type cloneOwner struct {
+	Name string
+}
+
+func TestClone_Struct(t *testing.T) {
+	one, two := 1, 2
+	owner := &cloneOwner{Name: "Alice"}
+	src := cloneOrder{
+		ID:      7,
+		Lines:   []cloneLine{{SKU: "a", Attrs: map[string][]int{"size": {1, 2}}}},
+		Empty:   []string{},
+		Meta:    map[string]any{"tags": []string{"x"}, "count": 3},
+		Owner:   owner,
+		Backup:  owner,
+		Fixed:   [2]*int{&one, &two},
+		Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		secret:  &one,
+	}
+
+	dst, err := Clone(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.ID != 7 {
+		t.Errorf("expected ID = 7, got %d", dst.ID)
+	}
+	if len(dst.Lines) != 1 || dst.Lines[0].SKU != "a" {
+		t.Fatalf("expected Lines = [{a ...}], got %+v", dst.Lines)
+	}
+	if size := dst.Lines[0].Attrs["size"]; len(size) != 2 || size[0] != 1 || size[1] != 2 {
+		t.Errorf("expected Lines[0].Attrs[size] = [1 2], got %v", size)
+	}
+	if tags, _ := dst.Meta["tags"].([]string); len(tags) != 1 || tags[0] != "x" || dst.Meta["count"] != 3 {
+		t.Errorf("expected Meta = map[count:3 tags:[x]], got %v", dst.Meta)
+	}
+	if dst.Owner == nil || dst.Owner.Name != "Alice" || dst.Backup == nil || dst.Backup.Name != "Alice" {
+		t.Errorf("expected Owner and Backup named Alice, got %+v, %+v", dst.Owner, dst.Backup)
+	}
+	if *dst.Fixed[0] != 1 || *dst.Fixed[1] != 2 {
+		t.Errorf("expected Fixed = [1 2], got [%d %d]", *dst.Fixed[0], *dst.Fixed[1])
+	}
+	if !dst.Created.Equal(src.Created) {
+		t.Errorf("expected Created = %v, got %v", src.Created, dst.Created)
+	}
+
+	if dst.Notes != nil || dst.Empty == nil {
+		t.Error("expected nil to stay nil and empty to stay empty")
+	}
+	if &dst.Lines[0] == &src.Lines[0] || reflect.ValueOf(dst.Lines[0].Attrs).Pointer() == reflect.ValueOf(src.Lines[0].Attrs).Pointer() {
+		t.Error("expected nested slices and maps to be copied")
+	}
+	if &dst.Lines[0].Attrs["size"][0] == &src.Lines[0].Attrs["size"][0] {
+		t.Error("expected slices inside maps to be copied")
+	}
+	if dst.Owner == src.Owner || dst.Fixed[0] == src.Fixed[0] {
+		t.Error("expected pointers to be copied")
+	}
+	if dst.Owner == dst.Backup {
+		t.Error("expected shared pointers to be copied separately without WithPreserveReferences")
+	}
+	if tags := dst.Meta["tags"].([]string); &tags[0] == &src.Meta["tags"].([]string)[0] {
+		t.Error("expected interface-held slices to be copied")
+	}
+	if dst.secret != src.secret {
+		t.Error("expected unexported fields to be copied shallowly")
+	}
+}
+
+func TestClone_NonStructValues(t *testing.T) {
+	owners := map[string]*cloneOwner{"a": {Name: "Alice"}}
+	copied, err := Clone(owners)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied["a"] == owners["a"] || copied["a"].Name != "Alice" {
+		t.Errorf("expected map values to be copied, got %+v", copied)
+	}
+
+	matrix := [][]int{{1, 2}, {3}}
+	grid, err := Clone(matrix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	grid[0][0] = 9
+	if matrix[0][0] != 1 {
+		t.Error("expected nested slices to be copied")
+	}
+
+	var nilPtr *cloneOwner
+	if got, err := Clone(nilPtr); err != nil || got != nil {
+		t.Errorf("expected nil pointer, got %v, %v", got, err)
+	}
+
+	var held any = &cloneOwner{Name: "Bob"}
+	got, err := Clone(held)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.(*cloneOwner) == held.(*cloneOwner) || got.(*cloneOwner).Name != "Bob" {
+		t.Errorf("expected interface-held pointer to be copied, got %+v", got)
+	}
+
+	var nilErr error
+	if got, err := Clone(nilErr); err != nil || got != nil {
+		t.Errorf("expected nil interface, got %v, %v", got, err)
+	}
+
+	if n, err := Clone(42); err != nil || n != 42 {
+		t.Errorf("expected 42, got %v, %v", n, err)
+	}
+}
+
+func TestClone_PreserveReferences(t *testing.T) {
+	first := &refListNode{Value: 1}
+	second := &refListNode{Value: 2, Prev: first}
+	first.Next = second
+	second.Next = first
+
+	if _, err := Clone(first, WithMaxDepth(16)); !errors.Is(err, ErrDepthExceeded) {
+		t.Fatalf("expected depth error for a cycle, got %v", err)
+	}
+
+	copied, err := Clone(first, WithPreserveReferences())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied == first || copied.Next.Next != copied || copied.Next.Prev != copied {
+		t.Errorf("expected the cycle to be copied, got %+v", copied)
+	}
+
+	owner := &cloneOwner{Name: "Alice"}
+	src := cloneOrder{Owner: owner, Backup: owner}
+	dst, err := Clone(src, WithPreserveReferences())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Owner != dst.Backup || dst.Owner == src.Owner {
+		t.Error("expected shared pointers to stay shared")
+	}
+}
+
+func TestClone_Converter(t *testing.T) {
+	src := cloneOrder{
+		Lines: []cloneLine{{SKU: "a", Attrs: map[string][]int{"size": {1, 2}}}},
+		Owner: &cloneOwner{Name: "Alice"},
+	}
+	dst, err := Clone(src, WithConverter(func(o cloneOwner) (cloneOwner, error) {
+		return cloneOwner{Name: o.Name + " (copy)"}, nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Owner.Name != "Alice (copy)" {
+		t.Errorf("expected converter to copy owners, got %q", dst.Owner.Name)
+	}
+
+	_, err = Clone(src, WithConverter(func(s []int) ([]int, error) {
+		return nil, errors.New("boom")
+	}))
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.FieldPath != "Lines[0].Attrs[size]" || mappingErr.Code != CodeConverterFailed {
+		t.Errorf("expected converter error at Lines[0].Attrs[size], got %v", err)
+	}
+}
@@ -0,0 +1,1026 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generatedMarker identifies files written by mapper-gen. Such files are
+// skipped when loading the package so stale output never breaks generation.
+const generatedMarker = "Code generated by mapper-gen. DO NOT EDIT."
+
+// typePair is a source and destination type name given on the command line.
+type typePair struct {
+	src string
+	dst string
+}
+
+// stringParser describes how a mapconv target type is parsed from a string.
+type stringParser struct {
+	call   string // parse call, %s is the string expression
+	result string // conversion of the parsed value, %s is the value
+	typ    types.Type
+}
+
+// stringParsers mirrors the mapconv parsers of the runtime engine.
+var stringParsers = map[string]stringParser{
+	"int":     {call: "strconv.ParseInt(%s, 10, 64)", result: "int(%s)", typ: types.Typ[types.Int]},
+	"int8":    {call: "strconv.ParseInt(%s, 10, 8)", result: "int8(%s)", typ: types.Typ[types.Int8]},
+	"int16":   {call: "strconv.ParseInt(%s, 10, 16)", result: "int16(%s)", typ: types.Typ[types.Int16]},
+	"int32":   {call: "strconv.ParseInt(%s, 10, 32)", result: "int32(%s)", typ: types.Typ[types.Int32]},
+	"int64":   {call: "strconv.ParseInt(%s, 10, 64)", result: "%s", typ: types.Typ[types.Int64]},
+	"uint":    {call: "strconv.ParseUint(%s, 10, 64)", result: "uint(%s)", typ: types.Typ[types.Uint]},
+	"uint8":   {call: "strconv.ParseUint(%s, 10, 8)", result: "uint8(%s)", typ: types.Typ[types.Uint8]},
+	"uint16":  {call: "strconv.ParseUint(%s, 10, 16)", result: "uint16(%s)", typ: types.Typ[types.Uint16]},
+	"uint32":  {call: "strconv.ParseUint(%s, 10, 32)", result: "uint32(%s)", typ: types.Typ[types.Uint32]},
+	"uint64":  {call: "strconv.ParseUint(%s, 10, 64)", result: "%s", typ: types.Typ[types.Uint64]},
+	"float32": {call: "strconv.ParseFloat(%s, 32)", result: "float32(%s)", typ: types.Typ[types.Float32]},
+	"float64": {call: "strconv.ParseFloat(%s, 64)", result: "%s", typ: types.Typ[types.Float64]},
+	"bool":    {call: "strconv.ParseBool(%s)", result: "%s", typ: types.Typ[types.Bool]},
+}
+
+// structField is an exported struct field as seen by the runtime metadata.
+type structField struct {
+	name      string
+	typ       types.Type
+	tag       string
+	convertTo string
+	// runtimeTag names a tag that only the runtime engine honors, such as
+	// mapmerge, mapkey or mapptr.
+	runtimeTag string
+}
+
+type helperKey struct {
+	src string
+	dst string
+}
+
+type helperJob struct {
+	name string
+	src  types.Type
+	dst  types.Type
+}
+
+type topFunc struct {
+	name   string
+	src    types.Type
+	dst    types.Type
+	helper string
+}
+
+type generator struct {
+	pkg     *types.Package
+	tagName string
+	imports map[string]string // import path -> package name
+
+	helpers map[helperKey]string
+	names   map[string]bool
+	pending []helperJob
+
+	body bytes.Buffer
+	tmp  int
+}
+
+// generate loads the package in dir and returns the formatted source of the
+// mapping functions for pairs.
+func generate(dir, tagName string, pairs []typePair) ([]byte, error) {
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		tagName: tagName,
+		imports: make(map[string]string),
+		helpers: make(map[helperKey]string),
+		names:   make(map[string]bool),
+	}
+
+	tops := make([]topFunc, 0, len(pairs))
+	for _, p := range pairs {
+		src, err := g.lookupStruct(p.src)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := g.lookupStruct(p.dst)
+		if err != nil {
+			return nil, err
+		}
+
+		name := "Map" + p.src + "To" + p.dst
+		if g.names[name] {
+			return nil, fmt.Errorf("duplicate type pair %s:%s", p.src, p.dst)
+		}
+		if pkg.Scope().Lookup(name) != nil {
+			return nil, fmt.Errorf("%s is already declared in package %s", name, pkg.Name())
+		}
+		g.names[name] = true
+
+		tops = append(tops, topFunc{name: name, src: src, dst: dst, helper: g.helperFor(src, dst)})
+	}
+
+	for len(g.pending) > 0 {
+		job := g.pending[0]
+		g.pending = g.pending[1:]
+		if err := g.emitHelper(job); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.assemble(tops)
+}
+
+// loadPackage parses and type-checks the non-test Go files in dir.
+// Type errors are tolerated because the package may reference functions
+// from a generated file that does not exist yet.
+func loadPackage(dir string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(f) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(bp.Name, fset, files, nil)
+	if pkg == nil {
+		return nil, fmt.Errorf("cannot type-check package in %s", dir)
+	}
+	return pkg, nil
+}
+
+func isGenerated(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		if strings.Contains(cg.Text(), generatedMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) lookupStruct(name string) (types.Type, error) {
+	obj := g.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("type %s not found in package %s", name, g.pkg.Name())
+	}
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a type", name)
+	}
+	named, ok := tn.Type().(*types.Named)
+	if ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("generic type %s is not supported", name)
+	}
+	if _, ok := tn.Type().Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("%s is not a struct type", name)
+	}
+	return tn.Type(), nil
+}
+
+// qualifier records imports for types declared in other packages.
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
+	g.imports[p.Path()] = p.Name()
+	return p.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// reflectName formats t the way reflect.Type.String does.
+func reflectName(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// conv returns the Go conversion of expr to t.
+func (g *generator) conv(t types.Type, from types.Type, expr string) string {
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+		if fb, ok := from.Underlying().(*types.Basic); ok && fb.Info()&types.IsInteger != 0 {
+			// Integer to string conversions yield a single rune, like reflect.Value.Convert.
+			expr = "rune(" + expr + ")"
+		}
+	}
+	ts := g.typeString(t)
+	if strings.HasPrefix(ts, "*") || strings.HasPrefix(ts, "func") || strings.HasPrefix(ts, "chan") || strings.HasPrefix(ts, "<-") {
+		return "(" + ts + ")(" + expr + ")"
+	}
+	return ts + "(" + expr + ")"
+}
+
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+func (g *generator) tmpName(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+// runtimeTags lists the destination field tags generated code cannot honor.
+var runtimeTags = []string{"mapmerge", "mapkey", "mapptr"}
+
+// structFields returns the exported fields of t in declaration order,
+// matching the runtime struct metadata.
+func (g *generator) structFields(t types.Type) []structField {
+	st := t.Underlying().(*types.Struct)
+	fields := make([]structField, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if !v.Exported() {
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i))
+		f := structField{name: v.Name(), typ: v.Type(), convertTo: tag.Get("mapconv")}
+		for _, name := range runtimeTags {
+			if _, ok := tag.Lookup(name); ok {
+				f.runtimeTag = name
+				break
+			}
+		}
+		if g.tagName != "" {
+			f.tag = tag.Get(g.tagName)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// hasComposite reports whether t has an exported field of a composite kind.
+func (g *generator) hasComposite(t types.Type) bool {
+	for _, f := range g.structFields(t) {
+		if isComposite(f.typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func isComposite(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Slice, *types.Map, *types.Pointer, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// isOptional reports whether t is a struct type with a Get() (T, bool)
+// method, which the runtime engine unwraps like mapper.Optional.
+func isOptional(t types.Type) bool {
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Get")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 2 {
+		return false
+	}
+	b, ok := sig.Results().At(1).Type().Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Bool
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.String
+}
+
+// helperFor returns the name of the helper mapping src into dst,
+// queueing it for emission on first use.
+func (g *generator) helperFor(src, dst types.Type) string {
+	key := helperKey{
+		src: types.TypeString(src, nil),
+		dst: types.TypeString(dst, nil),
+	}
+	if name, ok := g.helpers[key]; ok {
+		return name
+	}
+
+	base := "mapgen" + identFor(src) + "To" + identFor(dst)
+	name := base
+	for i := 2; g.names[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[name] = true
+	g.helpers[key] = name
+	g.pending = append(g.pending, helperJob{name: name, src: src, dst: dst})
+	return name
+}
+
+func identFor(t types.Type) string {
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return "Struct"
+}
+
+// addr returns an expression for the address of the addressable expression expr.
+func addr(expr string) string {
+	if strings.HasPrefix(expr, "(*") && strings.HasSuffix(expr, ")") && strings.Count(expr, "(") == 1 {
+		return expr[2 : len(expr)-1]
+	}
+	return "&" + expr
+}
+
+// emitHelper writes the field-by-field mapping function for a struct pair.
+func (g *generator) emitHelper(job helperJob) error {
+	g.tmp = 0
+
+	srcFields := g.structFields(job.src)
+	byName := make(map[string]structField, len(srcFields))
+	byTag := make(map[string]structField, len(srcFields))
+	for _, f := range srcFields {
+		byName[f.name] = f
+		if f.tag != "" {
+			byTag[f.tag] = f
+		}
+	}
+
+	g.p("func %s(dst *%s, src *%s) error {", job.name, g.typeString(job.dst), g.typeString(job.src))
+	if m := g.hookMethod(job.src, "BeforeMapTo"); m != "" {
+		g.emitHook("src."+m+"("+g.contextArg(m)+"dst)", m)
+	}
+	if m := g.hookMethod(job.dst, "BeforeMap"); m != "" {
+		g.emitHook("dst."+m+"("+g.contextArg(m)+"*src)", m)
+	}
+	for _, df := range g.structFields(job.dst) {
+		sf, ok := byName[df.name]
+		if !ok {
+			sf, ok = byTag[df.name]
+		}
+		if !ok {
+			g.p("// %s has no matching source field", df.name)
+			continue
+		}
+		if df.runtimeTag != "" {
+			return fmt.Errorf("%s -> %s: field %s: %s tag is not supported", reflectName(job.src), reflectName(job.dst), df.name, df.runtimeTag)
+		}
+
+		if err := g.emitAssign("dst."+df.name, "src."+sf.name, df.typ, sf.typ, sf.convertTo, strconv.Quote(df.name)); err != nil {
+			return fmt.Errorf("%s -> %s: field %s: %w", reflectName(job.src), reflectName(job.dst), df.name, err)
+		}
+	}
+	if m := g.hookMethod(job.dst, "AfterMap"); m != "" {
+		g.emitHook("dst."+m+"("+g.contextArg(m)+"*src)", m)
+	}
+	if m := g.hookMethod(job.src, "AfterMapTo"); m != "" {
+		g.emitHook("src."+m+"("+g.contextArg(m)+"dst)", m)
+	}
+	g.p("return nil")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// hookMethod returns the method of *t the runtime engine calls for the
+// mapping hook name: the context-aware variant nameContext if *t has it,
+// otherwise name, with the func(any) error signature. It returns "" if *t
+// has neither.
+func (g *generator) hookMethod(t types.Type, name string) string {
+	for _, m := range []string{name + "Context", name} {
+		sig := g.methodSig(t, m)
+		if sig != nil && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), errorType) {
+			return m
+		}
+	}
+	return ""
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// methodSig returns the signature of the method name of *t if it takes a
+// single parameter of an empty interface type, preceded by a context.Context
+// for context-aware methods whose name ends in Context, or nil.
+func (g *generator) methodSig(t types.Type, name string) *types.Signature {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, g.pkg, name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	params := 1
+	if isContextMethod(name) {
+		params = 2
+	}
+	if sig.Params().Len() != params || sig.Variadic() {
+		return nil
+	}
+	if params == 2 && !isContext(sig.Params().At(0).Type()) {
+		return nil
+	}
+	if param, ok := sig.Params().At(params - 1).Type().Underlying().(*types.Interface); !ok || !param.Empty() {
+		return nil
+	}
+	return sig
+}
+
+// isContextMethod reports whether the hook or self-mapping method name is a
+// context-aware variant.
+func isContextMethod(name string) bool {
+	return strings.HasSuffix(name, "Context")
+}
+
+// isContext reports whether t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// contextArg returns the leading context argument for a call of method.
+// Generated functions only run for calls without a context of their own, so
+// context-aware methods receive context.Background like in the engine.
+func (g *generator) contextArg(method string) string {
+	if !isContextMethod(method) {
+		return ""
+	}
+	g.imports["context"] = "context"
+	return "context.Background(), "
+}
+
+// hasHooks reports whether mapping sT into dT calls any mapping hook.
+func (g *generator) hasHooks(sT, dT types.Type) bool {
+	return g.hookMethod(sT, "BeforeMapTo") != "" || g.hookMethod(dT, "BeforeMap") != "" ||
+		g.hookMethod(dT, "AfterMap") != "" || g.hookMethod(sT, "AfterMapTo") != ""
+}
+
+// emitHook calls a mapping hook and wraps its error like the runtime engine.
+func (g *generator) emitHook(call, name string) {
+	g.p("if err := %s; err != nil {", call)
+	g.p("return &mapper.MappingError{Reason: %s + err.Error(), Code: mapper.CodeHookFailed, Err: err}", strconv.Quote(name+" hook failed: "))
+	g.p("}")
+}
+
+// emitAssign follows the field rules of the runtime engine: mapconv parsing,
+// primitive assignment and conversion, recursion into structs, slices and maps,
+// and pointer/value flexibility. path is a Go expression for the error path.
+func (g *generator) emitAssign(dst, src string, dT, sT types.Type, convertTo, path string) error {
+	if isOptional(sT) && !types.AssignableTo(sT, dT) {
+		return fmt.Errorf("optional source types are not supported: %s", reflectName(sT))
+	}
+
+	if convertTo != "" && isString(sT) {
+		return g.emitParse(dst, src, dT, sT, convertTo, path)
+	}
+
+	mapFrom := g.selfMapperMethod(dT, "MapFrom")
+	mapTo := g.selfMapperMethod(sT, "MapTo")
+	if mapFrom == "" && mapTo == "" {
+		return g.emitValue(dst, src, dT, sT, convertTo, path)
+	}
+
+	// Mirror assignSelf: MapFrom, then MapTo, then the built-in rules
+	h := g.tmpName("h")
+	g.p("{")
+	g.p("var %s bool", h)
+	g.p("var err error")
+	if mapFrom != "" {
+		g.emitSelfMap(h, dst+"."+mapFrom+"("+g.contextArg(mapFrom)+src+")", mapFrom, path)
+	}
+	if mapTo != "" {
+		if mapFrom != "" {
+			g.p("if !%s {", h)
+		}
+		g.emitSelfMap(h, src+"."+mapTo+"("+g.contextArg(mapTo)+addr(dst)+")", mapTo, path)
+		if mapFrom != "" {
+			g.p("}")
+		}
+	}
+	g.p("if !%s {", h)
+	mark := g.body.Len()
+	if err := g.emitValue(dst, src, dT, sT, convertTo, path); err != nil {
+		// The engine only rejects the types when neither method handles a value
+		g.body.Truncate(mark)
+		g.p("return &mapper.MappingError{FieldPath: %s, Reason: %s, Code: mapper.CodeIncompatibleTypes}", path, strconv.Quote(err.Error()))
+	}
+	g.p("}")
+	g.p("}")
+	return nil
+}
+
+// selfMapperMethod returns the method of *t the runtime engine calls for the
+// self-mapping method name, MapFrom or MapTo: the context-aware variant
+// nameContext if *t has it, otherwise name, with the func(any) (bool, error)
+// signature. It returns "" if *t has neither.
+func (g *generator) selfMapperMethod(t types.Type, name string) string {
+	if _, ok := t.(*types.Pointer); ok {
+		return ""
+	}
+	for _, m := range []string{name + "Context", name} {
+		sig := g.methodSig(t, m)
+		if sig != nil && sig.Results().Len() == 2 &&
+			types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool]) &&
+			types.Identical(sig.Results().At(1).Type(), errorType) {
+			return m
+		}
+	}
+	return ""
+}
+
+// emitSelfMap calls a MapFrom or MapTo method, storing whether it handled the
+// value in h, and wraps its error like the runtime engine.
+func (g *generator) emitSelfMap(h, call, name, path string) {
+	g.p("%s, err = %s", h, call)
+	g.p("if err != nil {")
+	g.p("return &mapper.MappingError{FieldPath: %s, Reason: %s + err.Error(), Code: mapper.CodeConverterFailed, Err: err}", path, strconv.Quote(name+" failed: "))
+	g.p("}")
+}
+
+// emitValue applies the kind-based rules of emitAssign.
+func (g *generator) emitValue(dst, src string, dT, sT types.Type, convertTo, path string) error {
+	if !isComposite(sT) && !isComposite(dT) {
+		return g.emitSimple(dst, src, dT, sT, "incompatible field types")
+	}
+
+	if types.IsInterface(sT) || types.IsInterface(dT) {
+		return g.emitInterface(dst, src, dT, sT, path)
+	}
+
+	sU := sT.Underlying()
+	dU := dT.Underlying()
+
+	switch s := sU.(type) {
+	case *types.Struct:
+		if _, ok := dU.(*types.Struct); ok {
+			return g.emitStruct(dst, src, dT, sT, path)
+		}
+	case *types.Slice:
+		if _, ok := dU.(*types.Slice); ok {
+			return g.emitSlice(dst, src, dT, sT, path)
+		}
+	case *types.Map:
+		if _, ok := dU.(*types.Map); ok {
+			return g.emitMap(dst, src, dT, sT, path)
+		}
+	case *types.Pointer:
+		if d, ok := dU.(*types.Pointer); ok {
+			v := g.tmpName("p")
+			g.p("if %s == nil {", src)
+			g.p("%s = nil", dst)
+			g.p("} else {")
+			g.p("%s := new(%s)", v, g.typeString(d.Elem()))
+			if err := g.emitAssign("(*"+v+")", "(*"+src+")", d.Elem(), s.Elem(), convertTo, path); err != nil {
+				return err
+			}
+			g.p("%s = %s", dst, v)
+			g.p("}")
+			return nil
+		}
+
+		g.p("if %s != nil {", src)
+		if err := g.emitAssign(dst, "(*"+src+")", dT, s.Elem(), convertTo, path); err != nil {
+			return err
+		}
+		g.p("}")
+		return nil
+	}
+
+	if d, ok := dU.(*types.Pointer); ok {
+		v := g.tmpName("p")
+		g.p("{")
+		g.p("%s := new(%s)", v, g.typeString(d.Elem()))
+		if err := g.emitAssign("(*"+v+")", src, d.Elem(), sT, convertTo, path); err != nil {
+			return err
+		}
+		g.p("%s = %s", dst, v)
+		g.p("}")
+		return nil
+	}
+
+	return g.emitSimple(dst, src, dT, sT, "incompatible field types")
+}
+
+// emitInterface deep copies a value into an interface destination like
+// assignInterface. The dynamic type of an interface source is only known at
+// run time, so it must be assignable to the destination as it is.
+func (g *generator) emitInterface(dst, src string, dT, sT types.Type, path string) error {
+	if !types.IsInterface(dT) {
+		return fmt.Errorf("interface source types are not supported: %s -> %s", reflectName(sT), reflectName(dT))
+	}
+	if !isComposite(sT) || !types.AssignableTo(sT, dT) {
+		return g.emitSimple(dst, src, dT, sT, "incompatible field types")
+	}
+
+	v := g.tmpName("v")
+	g.p("{")
+	g.p("%s, err := mapper.Clone(%s)", v, src)
+	g.p("if err != nil {")
+	g.p("return mappergenPrefix(err, %s)", path)
+	g.p("}")
+	g.p("%s = %s", dst, v)
+	g.p("}")
+	return nil
+}
+
+func (g *generator) emitSimple(dst, src string, dT, sT types.Type, reason string) error {
+	switch {
+	case types.AssignableTo(sT, dT):
+		g.p("%s = %s", dst, src)
+	case types.ConvertibleTo(sT, dT):
+		g.p("%s = %s", dst, g.conv(dT, sT, src))
+	default:
+		return fmt.Errorf("%s: %s -> %s", reason, reflectName(sT), reflectName(dT))
+	}
+	return nil
+}
+
+func (g *generator) emitParse(dst, src string, dT, sT types.Type, convertTo, path string) error {
+	parser, ok := stringParsers[convertTo]
+	if !ok {
+		return fmt.Errorf("unsupported mapconv target type: %s", convertTo)
+	}
+	if !types.ConvertibleTo(parser.typ, dT) {
+		return fmt.Errorf("incompatible field types: %s -> %s", convertTo, reflectName(dT))
+	}
+
+	str := src
+	if !types.Identical(sT, types.Typ[types.String]) {
+		str = "string(" + src + ")"
+	}
+
+	v := g.tmpName("v")
+	g.p("{")
+	g.p("%s, err := %s", v, fmt.Sprintf(parser.call, str))
+	g.p("if err != nil {")
+	g.p("return &mapper.MappingError{FieldPath: %s, Reason: %s + %s + %s + err.Error(), Code: mapper.CodeConversionFailed, Err: err}",
+		path, strconv.Quote(`cannot convert "`), str, strconv.Quote(`" to `+convertTo+": "))
+	g.p("}")
+	result := fmt.Sprintf(parser.result, v)
+	if types.Identical(parser.typ, dT) {
+		g.p("%s = %s", dst, result)
+	} else {
+		g.p("%s = %s", dst, g.conv(dT, parser.typ, result))
+	}
+	g.p("}")
+	return nil
+}
+
+// emitStruct assigns identical struct types without composite fields or hooks
+// directly and maps all other struct pairs through a helper.
+func (g *generator) emitStruct(dst, src string, dT, sT types.Type, path string) error {
+	if types.Identical(sT, dT) && !g.hasComposite(sT) && !g.hasHooks(sT, dT) {
+		g.p("%s = %s", dst, src)
+		return nil
+	}
+
+	name := g.helperFor(sT, dT)
+	g.p("if err := %s(%s, %s); err != nil {", name, addr(dst), addr(src))
+	g.p("return mappergenPrefix(err, %s)", path)
+	g.p("}")
+	return nil
+}
+
+// elemClass is the per-element strategy used for slice elements, map values
+// and pointer elements.
+type elemClass int
+
+const (
+	elemStructs elemClass = iota
+	elemSlices
+	elemMaps
+	elemPointers
+	elemAssignable
+	elemConvertible
+	elemIncompatible
+	// elemSelf is used when the types implement MapFrom or MapTo.
+	elemSelf
+	// elemInterface is used when either type is an interface.
+	elemInterface
+)
+
+func classify(sT, dT types.Type) elemClass {
+	sU := sT.Underlying()
+	dU := dT.Underlying()
+	switch sU.(type) {
+	case *types.Struct:
+		if _, ok := dU.(*types.Struct); ok {
+			return elemStructs
+		}
+	case *types.Slice:
+		if _, ok := dU.(*types.Slice); ok {
+			return elemSlices
+		}
+	case *types.Map:
+		if _, ok := dU.(*types.Map); ok {
+			return elemMaps
+		}
+	case *types.Pointer:
+		if _, ok := dU.(*types.Pointer); ok {
+			return elemPointers
+		}
+	}
+	if types.AssignableTo(sT, dT) {
+		return elemAssignable
+	}
+	if types.ConvertibleTo(sT, dT) {
+		return elemConvertible
+	}
+	return elemIncompatible
+}
+
+// classifyElem is classify for element types that may implement MapFrom or
+// MapTo or be interfaces.
+func (g *generator) classifyElem(sT, dT types.Type) elemClass {
+	if g.selfMapperMethod(dT, "MapFrom") != "" || g.selfMapperMethod(sT, "MapTo") != "" {
+		return elemSelf
+	}
+	if types.IsInterface(sT) || types.IsInterface(dT) {
+		return elemInterface
+	}
+	return classify(sT, dT)
+}
+
+func (g *generator) emitElem(class elemClass, dst, src string, dT, sT types.Type, path string) error {
+	switch class {
+	case elemSelf, elemInterface:
+		return g.emitAssign(dst, src, dT, sT, "", path)
+	case elemStructs:
+		return g.emitStruct(dst, src, dT, sT, path)
+	case elemSlices:
+		return g.emitSlice(dst, src, dT, sT, path)
+	case elemMaps:
+		return g.emitMap(dst, src, dT, sT, path)
+	case elemPointers:
+		return g.emitPointerElem(dst, src, dT, sT, path)
+	case elemAssignable:
+		g.p("%s = %s", dst, src)
+	case elemConvertible:
+		g.p("%s = %s", dst, g.conv(dT, sT, src))
+	}
+	return nil
+}
+
+// emitSlice mirrors assignSlice: nil stays nil, empty stays empty, and a new
+// backing array is always allocated.
+func (g *generator) emitSlice(dst, src string, dT, sT types.Type, path string) error {
+	sE := sT.Underlying().(*types.Slice).Elem()
+	dE := dT.Underlying().(*types.Slice).Elem()
+
+	class := g.classifyElem(sE, dE)
+	if class == elemIncompatible {
+		return fmt.Errorf("slice element types are incompatible: %s -> %s", reflectName(sE), reflectName(dE))
+	}
+
+	s := g.tmpName("s")
+	g.p("if %s == nil {", src)
+	g.p("%s = nil", dst)
+	g.p("} else {")
+	g.p("%s := make(%s, len(%s))", s, g.typeString(dT), src)
+	if types.Identical(sE, dE) && !isComposite(sE) && class != elemSelf {
+		g.p("copy(%s, %s)", s, src)
+	} else {
+		i := g.tmpName("i")
+		g.p("for %s := range %s {", i, src)
+		elemPath := fmt.Sprintf("mappergenIndex(%s, %s)", path, i)
+		if err := g.emitElem(class, s+"["+i+"]", src+"["+i+"]", dE, sE, elemPath); err != nil {
+			return err
+		}
+		g.p("}")
+	}
+	g.p("%s = %s", dst, s)
+	g.p("}")
+	return nil
+}
+
+// emitMap mirrors assignMap: nil stays nil, empty stays empty, and keys and
+// values are converted when their types differ.
+func (g *generator) emitMap(dst, src string, dT, sT types.Type, path string) error {
+	sMap := sT.Underlying().(*types.Map)
+	dMap := dT.Underlying().(*types.Map)
+	sK, dK := sMap.Key(), dMap.Key()
+	sV, dV := sMap.Elem(), dMap.Elem()
+
+	keysAssignable := types.AssignableTo(sK, dK)
+	if !keysAssignable && !types.ConvertibleTo(sK, dK) {
+		return fmt.Errorf("map key types are incompatible: %s -> %s", reflectName(sK), reflectName(dK))
+	}
+
+	class := g.classifyElem(sV, dV)
+	if class == elemIncompatible {
+		return fmt.Errorf("map value types are incompatible: %s -> %s", reflectName(sV), reflectName(dV))
+	}
+
+	m := g.tmpName("m")
+	k := g.tmpName("k")
+	v := g.tmpName("v")
+
+	g.p("if %s == nil {", src)
+	g.p("%s = nil", dst)
+	g.p("} else {")
+	g.p("%s := make(%s, len(%s))", m, g.typeString(dT), src)
+	g.p("for %s, %s := range %s {", k, v, src)
+
+	key := k
+	if !keysAssignable {
+		key = g.conv(dK, sK, k)
+	}
+
+	switch class {
+	case elemAssignable:
+		g.p("%s[%s] = %s", m, key, v)
+	case elemConvertible:
+		g.p("%s[%s] = %s", m, key, g.conv(dV, sV, v))
+	default:
+		dv := g.tmpName("dv")
+		g.p("var %s %s", dv, g.typeString(dV))
+		keyPath := fmt.Sprintf("mappergenKey(%s, %s)", path, keyString(k, sK))
+		if err := g.emitElem(class, dv, v, dV, sV, keyPath); err != nil {
+			return err
+		}
+		g.p("%s[%s] = %s", m, key, dv)
+	}
+
+	g.p("}")
+	g.p("%s = %s", dst, m)
+	g.p("}")
+	return nil
+}
+
+// keyString returns an expression formatting a map key like formatMapKey.
+func keyString(k string, t types.Type) string {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return strconv.Quote("<key>")
+	}
+	switch b.Kind() {
+	case types.String:
+		if types.Identical(t, types.Typ[types.String]) {
+			return k
+		}
+		return "string(" + k + ")"
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64:
+		return "strconv.FormatInt(int64(" + k + "), 10)"
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+		return "strconv.FormatUint(uint64(" + k + "), 10)"
+	default:
+		return strconv.Quote("<key>")
+	}
+}
+
+// emitPointerElem mirrors assignPointerElement for pointers inside slices and maps.
+func (g *generator) emitPointerElem(dst, src string, dT, sT types.Type, path string) error {
+	sE := sT.Underlying().(*types.Pointer).Elem()
+	dE := dT.Underlying().(*types.Pointer).Elem()
+
+	class := g.classifyElem(sE, dE)
+	if class == elemIncompatible {
+		return fmt.Errorf("incompatible pointer element types: %s -> %s", reflectName(sE), reflectName(dE))
+	}
+
+	p := g.tmpName("p")
+	g.p("if %s == nil {", src)
+	g.p("%s = nil", dst)
+	g.p("} else {")
+	g.p("%s := new(%s)", p, g.typeString(dE))
+	if err := g.emitElem(class, "(*"+p+")", "(*"+src+")", dE, sE, path); err != nil {
+		return err
+	}
+	g.p("%s = %s", dst, p)
+	g.p("}")
+	return nil
+}
+
+// assemble writes the file header, imports, registrations, exported functions,
+// helpers and support functions, and formats the result.
+func (g *generator) assemble(tops []topFunc) ([]byte, error) {
+	var top bytes.Buffer
+	for _, t := range tops {
+		srcName := g.typeString(t.src)
+		dstName := g.typeString(t.dst)
+		fmt.Fprintf(&top, "// %s maps src into dst. It applies the same rules as\n", t.name)
+		fmt.Fprintf(&top, "// mapper.MapWithOptions(dst, src, mapper.WithTagName(%q)).\n", g.tagName)
+		fmt.Fprintf(&top, "func %s(dst *%s, src *%s) error {\n", t.name, dstName, srcName)
+		fmt.Fprintf(&top, "if dst == nil {\n")
+		fmt.Fprintf(&top, "return &mapper.MappingError{SrcType: %q, DstType: %q, Reason: \"dst must be a non-nil pointer to struct\", Code: mapper.CodeNilInput}\n",
+			"*"+reflectName(t.src), "*"+reflectName(t.dst))
+		fmt.Fprintf(&top, "}\n")
+		fmt.Fprintf(&top, "if src == nil {\n")
+		fmt.Fprintf(&top, "return &mapper.MappingError{SrcType: %q, DstType: %q, Reason: \"src is a nil pointer\", Code: mapper.CodeNilInput}\n",
+			"*"+reflectName(t.src), "*"+reflectName(t.dst))
+		fmt.Fprintf(&top, "}\n")
+		fmt.Fprintf(&top, "if err := %s(dst, src); err != nil {\n", t.helper)
+		fmt.Fprintf(&top, "return mappergenWithTypes(err, %q, %q)\n", reflectName(t.src), reflectName(t.dst))
+		fmt.Fprintf(&top, "}\n")
+		fmt.Fprintf(&top, "return nil\n")
+		fmt.Fprintf(&top, "}\n\n")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// %s\n\n", generatedMarker)
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg.Name())
+
+	std := []string{"strconv"}
+	var others []string
+	for path := range g.imports {
+		if path == "strconv" {
+			continue
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	others = append(others, "github.com/tariklabs/mapper")
+	sort.Strings(std)
+	sort.Strings(others)
+
+	out.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&out, "%q\n", path)
+	}
+	out.WriteString("\n")
+	for _, path := range others {
+		fmt.Fprintf(&out, "%q\n", path)
+	}
+	out.WriteString(")\n\n")
+
+	out.WriteString("func init() {\n")
+	for _, t := range tops {
+		fmt.Fprintf(&out, "mapper.RegisterGenerated(%s, mapper.WithTagName(%q))\n", t.name, g.tagName)
+	}
+	out.WriteString("}\n\n")
+
+	out.Write(top.Bytes())
+	out.Write(g.body.Bytes())
+	out.WriteString(supportCode)
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return code, nil
+}
+
+// supportCode is appended to every generated file. It builds error paths
+// lazily, the same way the runtime engine does.
+const supportCode = `
+// mappergenPrefix prepends prefix to the field path of a mapping error.
+func mappergenPrefix(err error, prefix string) error {
+	if me, ok := err.(*mapper.MappingError); ok {
+		switch {
+		case me.FieldPath == "":
+			me.FieldPath = prefix
+		case me.FieldPath[0] == '[':
+			me.FieldPath = prefix + me.FieldPath
+		default:
+			me.FieldPath = prefix + "." + me.FieldPath
+		}
+	}
+	return err
+}
+
+// mappergenWithTypes sets the top-level source and destination types of a mapping error.
+func mappergenWithTypes(err error, srcType, dstType string) error {
+	if me, ok := err.(*mapper.MappingError); ok {
+		me.SrcType = srcType
+		me.DstType = dstType
+	}
+	return err
+}
+
+// mappergenIndex builds the path of a slice element.
+func mappergenIndex(base string, index int) string {
+	return base + "[" + strconv.Itoa(index) + "]"
+}
+
+// mappergenKey builds the path of a map entry.
+func mappergenKey(base, key string) string {
+	return base + "[" + key + "]"
+}
+`
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_ExampleUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+
+	code, err := generate(dir, "map", []typePair{{src: "OrderDTO", dst: "Order"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	existing, err := os.ReadFile(filepath.Join(dir, "mapper_gen.go"))
+	if err != nil {
+		t.Fatalf("reading generated file: %v", err)
+	}
+
+	if !bytes.Equal(code, existing) {
+		t.Error("internal/example/mapper_gen.go is stale; run go generate ./cmd/mapper-gen/internal/example")
+	}
+}
+
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerate_TagName(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+type Src struct {
+	FullName string `+"`json:\"Name\"`"+`
+}
+
+type Dst struct {
+	Name string
+}
+`)
+
+	code, err := generate(dir, "json", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(code)
+	if !strings.Contains(out, "dst.Name = src.FullName") {
+		t.Errorf("expected tag alias to be resolved, got:\n%s", out)
+	}
+	if !strings.Contains(out, `mapper.RegisterGenerated(MapSrcToDst, mapper.WithTagName("json"))`) {
+		t.Errorf("expected registration with the json tag name, got:\n%s", out)
+	}
+}
+
+func TestGenerate_SkipsPreviousOutput(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+type Src struct{ Name string }
+
+type Dst struct{ Name string }
+
+func use() { _ = MapSrcToDst }
+`)
+
+	code, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mapper_gen.go"), code, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error on regeneration: %v", err)
+	}
+	if !bytes.Equal(code, again) {
+		t.Error("expected regeneration to be stable")
+	}
+}
+
+func TestGenerate_Hooks(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+type Item struct{ ID int }
+
+func (i *Item) BeforeMap(src any) error { return nil }
+
+type Src struct{ Items []Item }
+
+func (s Src) BeforeMapTo(dst any) error { return nil }
+
+func (s *Src) AfterMapTo(dst any) error { return nil }
+
+type Dst struct{ Items []Item }
+
+func (d *Dst) AfterMap(src Src) error { return nil }
+`)
+
+	code, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(code)
+	for _, call := range []string{"src.BeforeMapTo(dst)", "src.AfterMapTo(dst)", "dst.BeforeMap(*src)", "mapgenItemToItem("} {
+		if !strings.Contains(out, call) {
+			t.Errorf("expected %s in generated code, got:\n%s", call, out)
+		}
+	}
+	if strings.Contains(out, "dst.AfterMap(") {
+		t.Errorf("expected AfterMap with a different signature to be ignored, got:\n%s", out)
+	}
+}
+
+func TestGenerate_SelfMapping(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+type ID struct{ v string }
+
+func (id *ID) MapFrom(src any) (bool, error) { return false, nil }
+
+type Code string
+
+func (c Code) MapTo(dst any) (bool, error) { return false, nil }
+
+type Src struct {
+	ID   string
+	IDs  map[string]Code
+	Code Code
+}
+
+type Dst struct {
+	ID   ID
+	IDs  map[string]ID
+	Code string
+}
+`)
+
+	code, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(code)
+	for _, call := range []string{"dst.ID.MapFrom(src.ID)", ".MapTo(&dv", "src.Code.MapTo(&dst.Code)", "dst.Code = string(src.Code)"} {
+		if !strings.Contains(out, call) {
+			t.Errorf("expected %s in generated code, got:\n%s", call, out)
+		}
+	}
+	if !strings.Contains(out, `Reason: "incompatible field types: string -> fixture.ID"`) {
+		t.Errorf("expected unhandled incompatible values to fail at runtime, got:\n%s", out)
+	}
+}
+
+func TestGenerate_ContextMethods(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+import "context"
+
+type ID struct{ v string }
+
+func (id *ID) MapFrom(src any) (bool, error) { return false, nil }
+
+func (id *ID) MapFromContext(ctx context.Context, src any) (bool, error) { return false, nil }
+
+type Src struct{ ID string }
+
+func (s Src) AfterMapToContext(ctx context.Context, dst any) error { return nil }
+
+type Dst struct{ ID ID }
+
+func (d *Dst) BeforeMap(src any) error { return nil }
+
+func (d *Dst) BeforeMapContext(ctx context.Context, src any) error { return nil }
+
+func (d *Dst) AfterMapContext(src any) error { return nil }
+`)
+
+	code, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(code)
+	for _, call := range []string{
+		`"context"`,
+		"dst.BeforeMapContext(context.Background(), *src)",
+		"src.AfterMapToContext(context.Background(), dst)",
+		"dst.ID.MapFromContext(context.Background(), src.ID)",
+		`"BeforeMapContext hook failed: "`,
+	} {
+		if !strings.Contains(out, call) {
+			t.Errorf("expected %s in generated code, got:\n%s", call, out)
+		}
+	}
+	for _, call := range []string{"dst.BeforeMap(*src)", "dst.ID.MapFrom(src.ID)", "AfterMapContext("} {
+		if strings.Contains(out, call) {
+			t.Errorf("expected %s not to be called, got:\n%s", call, out)
+		}
+	}
+}
+
+func TestGenerate_Interfaces(t *testing.T) {
+	dir := writePackage(t, `package fixture
+
+type Item struct{ ID int }
+
+type Src struct {
+	Any   any
+	Item  Item
+	Count int
+	Items []any
+}
+
+type Dst struct {
+	Any   any
+	Item  any
+	Count any
+	Items []any
+}
+`)
+
+	code, err := generate(dir, "map", []typePair{{src: "Src", dst: "Dst"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(code)
+	for _, call := range []string{"mapper.Clone(src.Any)", "mapper.Clone(src.Item)", "dst.Count = src.Count", "mapper.Clone(src.Items["} {
+		if !strings.Contains(out, call) {
+			t.Errorf("expected %s in generated code, got:\n%s", call, out)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		pair  typePair
+		wants string
+	}{
+		{
+			name:  "unknown type",
+			src:   "package fixture\n\ntype Dst struct{}\n",
+			pair:  typePair{src: "Missing", dst: "Dst"},
+			wants: "type Missing not found",
+		},
+		{
+			name:  "not a struct",
+			src:   "package fixture\n\ntype Src int\n\ntype Dst struct{}\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "Src is not a struct type",
+		},
+		{
+			name:  "incompatible field types",
+			src:   "package fixture\n\ntype Src struct{ V string }\n\ntype Dst struct{ V int }\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "incompatible field types: string -> int",
+		},
+		{
+			name:  "unsupported mapconv",
+			src:   "package fixture\n\ntype Src struct {\n\tV string `mapconv:\"complex128\"`\n}\n\ntype Dst struct{ V complex128 }\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "unsupported mapconv target type: complex128",
+		},
+		{
+			name:  "incompatible slice elements",
+			src:   "package fixture\n\ntype Src struct{ V []string }\n\ntype Dst struct{ V []int }\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "slice element types are incompatible: string -> int",
+		},
+		{
+			name:  "merge tags",
+			src:   "package fixture\n\ntype Item struct{ ID int }\n\ntype Src struct{ V []Item }\n\ntype Dst struct {\n\tV []Item `mapkey:\"ID\"`\n}\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "mapkey tag is not supported",
+		},
+		{
+			name:  "optional source",
+			src:   "package fixture\n\ntype Opt struct{ v string }\n\nfunc (o Opt) Get() (string, bool) { return o.v, o.v != \"\" }\n\ntype Src struct{ V Opt }\n\ntype Dst struct{ V string }\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "optional source types are not supported",
+		},
+		{
+			name:  "interface source",
+			src:   "package fixture\n\ntype Src struct{ V any }\n\ntype Dst struct{ V int }\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "interface source types are not supported: any -> int",
+		},
+		{
+			name:  "pointer tags",
+			src:   "package fixture\n\ntype Item struct{ ID int }\n\ntype Src struct{ V *Item }\n\ntype Dst struct {\n\tV *Item `mapptr:\"reuse\"`\n}\n",
+			pair:  typePair{src: "Src", dst: "Dst"},
+			wants: "mapptr tag is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePackage(t, tt.src)
+
+			_, err := generate(dir, "map", []typePair{tt.pair})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wants) {
+				t.Errorf("expected error containing %q, got %q", tt.wants, err.Error())
+			}
+		})
+	}
+}
+
+func TestRun_InvalidPair(t *testing.T) {
+	err := run(".", "out.go", "map", []string{"OnlySource"})
+	if err == nil || !strings.Contains(err.Error(), "invalid type pair") {
+		t.Errorf("expected invalid type pair error, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package example
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tariklabs/mapper"
+)
+
+func buildOrderDTO() OrderDTO {
+	paid := "yes"
+	count := int32(7)
+	return OrderDTO{
+		ID:       42,
+		Customer: "Rafa",
+		Total:    "99.5",
+		Paid:     &paid,
+		Notes:    "leave at door",
+		Shipping: AddressDTO{Street: "1 Main St", City: "Seattle", Zip: "98101"},
+		Billing:  &AddressDTO{Street: "2 Side St", City: "Tacoma", Zip: "98402"},
+		Lines: []LineDTO{
+			{SKU: "A-1", Quantity: "2", Price: 1.5},
+			{SKU: "B-2", Quantity: "1", Price: 3.25},
+		},
+		Refs:      []*LineDTO{{SKU: "R-1", Quantity: "3"}, nil},
+		Tags:      []string{"gift"},
+		Scores:    []int32{1, 2, 3},
+		Grid:      [][]LineDTO{{{SKU: "G", Quantity: "4"}}, nil, {}},
+		Attrs:     map[string]string{"channel": "web"},
+		Extra:     map[string]any{"gift": true, "labels": []string{"fragile"}},
+		ByRegion:  map[string]AddressDTO{"eu": {City: "Berlin", Zip: "10115"}},
+		Counts:    map[int32]*int32{1: &count, 2: nil},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Discount:  "2.50",
+		Coupons:   []string{"1", "0.25"},
+		Internal:  "dropped",
+	}
+}
+
+// engineOptions forces the reflection engine: the generated function is only
+// used when the configuration matches the one it was generated for.
+var engineOptions = []mapper.Option{mapper.WithMaxDepth(mapper.DefaultMaxDepth + 1)}
+
+func TestGenerated_MatchesEngine(t *testing.T) {
+	src := buildOrderDTO()
+
+	var generated Order
+	if err := MapOrderDTOToOrder(&generated, &src); err != nil {
+		t.Fatalf("unexpected error from generated function: %v", err)
+	}
+
+	var engine Order
+	if err := mapper.MapWithOptions(&engine, src, engineOptions...); err != nil {
+		t.Fatalf("unexpected error from engine: %v", err)
+	}
+
+	if !reflect.DeepEqual(generated, engine) {
+		t.Errorf("generated result differs from engine result:\ngenerated: %+v\nengine:    %+v", generated, engine)
+	}
+}
+
+func TestGenerated_NilAndEmptyCollections(t *testing.T) {
+	src := OrderDTO{Total: "0", Shipping: AddressDTO{Zip: "0"}, Lines: []LineDTO{}, Attrs: map[string]string{}}
+
+	var dst Order
+	if err := MapOrderDTOToOrder(&dst, &src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Lines == nil || len(dst.Lines) != 0 {
+		t.Errorf("expected empty non-nil Lines, got %#v", dst.Lines)
+	}
+	if dst.Attrs == nil || len(dst.Attrs) != 0 {
+		t.Errorf("expected empty non-nil Attrs, got %#v", dst.Attrs)
+	}
+	if dst.Tags != nil || dst.ByRegion != nil || dst.Billing != nil {
+		t.Error("expected nil source collections and pointers to stay nil")
+	}
+}
+
+func TestGenerated_DeepCopy(t *testing.T) {
+	src := buildOrderDTO()
+
+	var dst Order
+	if err := MapOrderDTOToOrder(&dst, &src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src.Tags[0] = "changed"
+	src.Attrs["channel"] = "changed"
+	src.Extra["labels"].([]string)[0] = "changed"
+	*src.Paid = "changed"
+
+	if dst.Tags[0] != "gift" || dst.Attrs["channel"] != "web" || dst.Extra["labels"].([]string)[0] != "fragile" || *dst.Paid != "yes" {
+		t.Error("expected destination not to share memory with the source")
+	}
+}
+
+func TestGenerated_ErrorsMatchEngine(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*OrderDTO)
+		path   string
+	}{
+		{"top-level field", func(o *OrderDTO) { o.Total = "x" }, "Total"},
+		{"nested struct", func(o *OrderDTO) { o.Shipping.Zip = "x" }, "Shipping.Zip"},
+		{"nested pointer", func(o *OrderDTO) { o.Billing.Zip = "x" }, "Billing.Zip"},
+		{"slice element", func(o *OrderDTO) { o.Lines[1].Quantity = "x" }, "Lines[1].Quantity"},
+		{"nested slice element", func(o *OrderDTO) { o.Grid[0][0].Quantity = "x" }, "Grid[0][0].Quantity"},
+		{"map value", func(o *OrderDTO) { o.ByRegion["eu"] = AddressDTO{Zip: "x"} }, "ByRegion[eu].Zip"},
+		{"self-mapped field", func(o *OrderDTO) { o.Discount = "x" }, "Discount"},
+		{"self-mapped slice element", func(o *OrderDTO) { o.Coupons[1] = "x" }, "Coupons[1]"},
+		{"slice element hook", func(o *OrderDTO) { o.Lines[1].Quantity = "-1" }, "Lines[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := buildOrderDTO()
+			tt.mutate(&src)
+
+			var generated, engine Order
+			genErr := MapOrderDTOToOrder(&generated, &src)
+			engErr := mapper.MapWithOptions(&engine, src, engineOptions...)
+
+			if genErr == nil || engErr == nil {
+				t.Fatalf("expected errors, got generated=%v engine=%v", genErr, engErr)
+			}
+			if genErr.Error() != engErr.Error() {
+				t.Errorf("error mismatch:\ngenerated: %v\nengine:    %v", genErr, engErr)
+			}
+
+			mappingErr, ok := genErr.(*mapper.MappingError)
+			if !ok {
+				t.Fatalf("expected *mapper.MappingError, got %T", genErr)
+			}
+			if mappingErr.FieldPath != tt.path {
+				t.Errorf("expected FieldPath = %q, got %q", tt.path, mappingErr.FieldPath)
+			}
+		})
+	}
+}
+
+func TestGenerated_MapDispatch(t *testing.T) {
+	src := buildOrderDTO()
+
+	var viaMap Order
+	if err := mapper.Map(&viaMap, &src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var direct Order
+	if err := MapOrderDTOToOrder(&direct, &src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(viaMap, direct) {
+		t.Error("expected mapper.Map to produce the generated result")
+	}
+}
+
+func TestGenerated_NilArguments(t *testing.T) {
+	if err := MapOrderDTOToOrder(nil, &OrderDTO{}); err == nil {
+		t.Error("expected error for nil dst")
+	}
+	if err := MapOrderDTOToOrder(&Order{}, nil); err == nil {
+		t.Error("expected error for nil src")
+	}
+}
@@ -0,0 +1,280 @@
+// Code generated by mapper-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"strconv"
+
+	"github.com/tariklabs/mapper"
+)
+
+func init() {
+	mapper.RegisterGenerated(MapOrderDTOToOrder, mapper.WithTagName("map"))
+}
+
+// MapOrderDTOToOrder maps src into dst. It applies the same rules as
+// mapper.MapWithOptions(dst, src, mapper.WithTagName("map")).
+func MapOrderDTOToOrder(dst *Order, src *OrderDTO) error {
+	if dst == nil {
+		return &mapper.MappingError{SrcType: "*example.OrderDTO", DstType: "*example.Order", Reason: "dst must be a non-nil pointer to struct", Code: mapper.CodeNilInput}
+	}
+	if src == nil {
+		return &mapper.MappingError{SrcType: "*example.OrderDTO", DstType: "*example.Order", Reason: "src is a nil pointer", Code: mapper.CodeNilInput}
+	}
+	if err := mapgenOrderDTOToOrder(dst, src); err != nil {
+		return mappergenWithTypes(err, "example.OrderDTO", "example.Order")
+	}
+	return nil
+}
+
+func mapgenOrderDTOToOrder(dst *Order, src *OrderDTO) error {
+	dst.ID = int64(src.ID)
+	dst.CustomerName = src.Customer
+	{
+		v1, err := strconv.ParseFloat(src.Total, 64)
+		if err != nil {
+			return &mapper.MappingError{FieldPath: "Total", Reason: "cannot convert \"" + src.Total + "\" to float64: " + err.Error(), Code: mapper.CodeConversionFailed, Err: err}
+		}
+		dst.Total = v1
+	}
+	if src.Paid == nil {
+		dst.Paid = nil
+	} else {
+		p2 := new(string)
+		(*p2) = (*src.Paid)
+		dst.Paid = p2
+	}
+	{
+		p3 := new(string)
+		(*p3) = src.Notes
+		dst.Notes = p3
+	}
+	if err := mapgenAddressDTOToAddress(&dst.Shipping, &src.Shipping); err != nil {
+		return mappergenPrefix(err, "Shipping")
+	}
+	if src.Billing == nil {
+		dst.Billing = nil
+	} else {
+		p4 := new(Address)
+		if err := mapgenAddressDTOToAddress(p4, src.Billing); err != nil {
+			return mappergenPrefix(err, "Billing")
+		}
+		dst.Billing = p4
+	}
+	if src.Lines == nil {
+		dst.Lines = nil
+	} else {
+		s5 := make([]Line, len(src.Lines))
+		for i6 := range src.Lines {
+			if err := mapgenLineDTOToLine(&s5[i6], &src.Lines[i6]); err != nil {
+				return mappergenPrefix(err, mappergenIndex("Lines", i6))
+			}
+		}
+		dst.Lines = s5
+	}
+	if src.Refs == nil {
+		dst.Refs = nil
+	} else {
+		s7 := make([]*Line, len(src.Refs))
+		for i8 := range src.Refs {
+			if src.Refs[i8] == nil {
+				s7[i8] = nil
+			} else {
+				p9 := new(Line)
+				if err := mapgenLineDTOToLine(p9, src.Refs[i8]); err != nil {
+					return mappergenPrefix(err, mappergenIndex("Refs", i8))
+				}
+				s7[i8] = p9
+			}
+		}
+		dst.Refs = s7
+	}
+	if src.Tags == nil {
+		dst.Tags = nil
+	} else {
+		s10 := make([]string, len(src.Tags))
+		copy(s10, src.Tags)
+		dst.Tags = s10
+	}
+	if src.Scores == nil {
+		dst.Scores = nil
+	} else {
+		s11 := make([]int64, len(src.Scores))
+		for i12 := range src.Scores {
+			s11[i12] = int64(src.Scores[i12])
+		}
+		dst.Scores = s11
+	}
+	if src.Grid == nil {
+		dst.Grid = nil
+	} else {
+		s13 := make([][]Line, len(src.Grid))
+		for i14 := range src.Grid {
+			if src.Grid[i14] == nil {
+				s13[i14] = nil
+			} else {
+				s15 := make([]Line, len(src.Grid[i14]))
+				for i16 := range src.Grid[i14] {
+					if err := mapgenLineDTOToLine(&s15[i16], &src.Grid[i14][i16]); err != nil {
+						return mappergenPrefix(err, mappergenIndex(mappergenIndex("Grid", i14), i16))
+					}
+				}
+				s13[i14] = s15
+			}
+		}
+		dst.Grid = s13
+	}
+	if src.Attrs == nil {
+		dst.Attrs = nil
+	} else {
+		m17 := make(map[string]string, len(src.Attrs))
+		for k18, v19 := range src.Attrs {
+			m17[k18] = v19
+		}
+		dst.Attrs = m17
+	}
+	if src.Extra == nil {
+		dst.Extra = nil
+	} else {
+		m20 := make(map[string]any, len(src.Extra))
+		for k21, v22 := range src.Extra {
+			var dv23 any
+			{
+				v24, err := mapper.Clone(v22)
+				if err != nil {
+					return mappergenPrefix(err, mappergenKey("Extra", k21))
+				}
+				dv23 = v24
+			}
+			m20[k21] = dv23
+		}
+		dst.Extra = m20
+	}
+	if src.ByRegion == nil {
+		dst.ByRegion = nil
+	} else {
+		m25 := make(map[string]Address, len(src.ByRegion))
+		for k26, v27 := range src.ByRegion {
+			var dv28 Address
+			if err := mapgenAddressDTOToAddress(&dv28, &v27); err != nil {
+				return mappergenPrefix(err, mappergenKey("ByRegion", k26))
+			}
+			m25[k26] = dv28
+		}
+		dst.ByRegion = m25
+	}
+	if src.Counts == nil {
+		dst.Counts = nil
+	} else {
+		m29 := make(map[int64]*int64, len(src.Counts))
+		for k30, v31 := range src.Counts {
+			var dv32 *int64
+			if v31 == nil {
+				dv32 = nil
+			} else {
+				p33 := new(int64)
+				(*p33) = int64((*v31))
+				dv32 = p33
+			}
+			m29[int64(k30)] = dv32
+		}
+		dst.Counts = m29
+	}
+	dst.CreatedAt = src.CreatedAt
+	{
+		var h34 bool
+		var err error
+		h34, err = dst.Discount.MapFrom(src.Discount)
+		if err != nil {
+			return &mapper.MappingError{FieldPath: "Discount", Reason: "MapFrom failed: " + err.Error(), Code: mapper.CodeConverterFailed, Err: err}
+		}
+		if !h34 {
+			return &mapper.MappingError{FieldPath: "Discount", Reason: "incompatible field types: string -> example.Money", Code: mapper.CodeIncompatibleTypes}
+		}
+	}
+	if src.Coupons == nil {
+		dst.Coupons = nil
+	} else {
+		s35 := make([]Money, len(src.Coupons))
+		for i36 := range src.Coupons {
+			{
+				var h37 bool
+				var err error
+				h37, err = s35[i36].MapFrom(src.Coupons[i36])
+				if err != nil {
+					return &mapper.MappingError{FieldPath: mappergenIndex("Coupons", i36), Reason: "MapFrom failed: " + err.Error(), Code: mapper.CodeConverterFailed, Err: err}
+				}
+				if !h37 {
+					return &mapper.MappingError{FieldPath: mappergenIndex("Coupons", i36), Reason: "incompatible field types: string -> example.Money", Code: mapper.CodeIncompatibleTypes}
+				}
+			}
+		}
+		dst.Coupons = s35
+	}
+	// Unmatched has no matching source field
+	return nil
+}
+
+func mapgenAddressDTOToAddress(dst *Address, src *AddressDTO) error {
+	dst.Street = src.Street
+	dst.City = src.City
+	{
+		v1, err := strconv.ParseInt(src.Zip, 10, 64)
+		if err != nil {
+			return &mapper.MappingError{FieldPath: "Zip", Reason: "cannot convert \"" + src.Zip + "\" to int: " + err.Error(), Code: mapper.CodeConversionFailed, Err: err}
+		}
+		dst.Zip = int(v1)
+	}
+	return nil
+}
+
+func mapgenLineDTOToLine(dst *Line, src *LineDTO) error {
+	dst.Code = src.SKU
+	{
+		v1, err := strconv.ParseInt(src.Quantity, 10, 64)
+		if err != nil {
+			return &mapper.MappingError{FieldPath: "Quantity", Reason: "cannot convert \"" + src.Quantity + "\" to int: " + err.Error(), Code: mapper.CodeConversionFailed, Err: err}
+		}
+		dst.Quantity = int(v1)
+	}
+	dst.Price = float64(src.Price)
+	// Subtotal has no matching source field
+	if err := dst.AfterMap(*src); err != nil {
+		return &mapper.MappingError{Reason: "AfterMap hook failed: " + err.Error(), Code: mapper.CodeHookFailed, Err: err}
+	}
+	return nil
+}
+
+// mappergenPrefix prepends prefix to the field path of a mapping error.
+func mappergenPrefix(err error, prefix string) error {
+	if me, ok := err.(*mapper.MappingError); ok {
+		switch {
+		case me.FieldPath == "":
+			me.FieldPath = prefix
+		case me.FieldPath[0] == '[':
+			me.FieldPath = prefix + me.FieldPath
+		default:
+			me.FieldPath = prefix + "." + me.FieldPath
+		}
+	}
+	return err
+}
+
+// mappergenWithTypes sets the top-level source and destination types of a mapping error.
+func mappergenWithTypes(err error, srcType, dstType string) error {
+	if me, ok := err.(*mapper.MappingError); ok {
+		me.SrcType = srcType
+		me.DstType = dstType
+	}
+	return err
+}
+
+// mappergenIndex builds the path of a slice element.
+func mappergenIndex(base string, index int) string {
+	return base + "[" + strconv.Itoa(index) + "]"
+}
+
+// mappergenKey builds the path of a map entry.
+func mappergenKey(base, key string) string {
+	return base + "[" + key + "]"
+}
@@ -0,0 +1,123 @@
+// Package example declares the fixture types used to test mapper-gen.
+// The generated mapping functions live in mapper_gen.go.
+package example
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+//go:generate go run github.com/tariklabs/mapper/cmd/mapper-gen -output mapper_gen.go OrderDTO:Order
+
+// AddressDTO is a nested source struct.
+type AddressDTO struct {
+	Street string
+	City   string
+	Zip    string `mapconv:"int"`
+}
+
+// Address is a nested destination struct.
+type Address struct {
+	Street string
+	City   string
+	Zip    int
+}
+
+// LineDTO is a slice element source struct.
+type LineDTO struct {
+	SKU      string `map:"Code"`
+	Quantity string `mapconv:"int"`
+	Price    float32
+}
+
+// Line is a slice element destination struct.
+type Line struct {
+	Code     string
+	Quantity int
+	Price    float64
+	Subtotal float64
+}
+
+// AfterMap derives Subtotal once the line is mapped.
+func (l *Line) AfterMap(src any) error {
+	if l.Quantity < 0 {
+		return errors.New("negative quantity")
+	}
+	l.Subtotal = l.Price * float64(l.Quantity)
+	return nil
+}
+
+// Money is a value object with unexported state that maps itself from a
+// decimal string.
+type Money struct {
+	cents int64
+}
+
+// MapFrom parses a decimal string such as "12.50"; empty is zero.
+func (m *Money) MapFrom(src any) (bool, error) {
+	s, ok := src.(string)
+	if !ok {
+		return false, nil
+	}
+	if s == "" {
+		*m = Money{}
+		return true, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return true, err
+	}
+	m.cents = int64(f*100 + 0.5)
+	return true, nil
+}
+
+// OrderDTO exercises every rule supported by the generator.
+type OrderDTO struct {
+	ID        int32
+	Customer  string `map:"CustomerName"`
+	Total     string `mapconv:"float64"`
+	Paid      *string
+	Notes     string
+	Shipping  AddressDTO
+	Billing   *AddressDTO
+	Lines     []LineDTO
+	Refs      []*LineDTO
+	Tags      []string
+	Scores    []int32
+	Grid      [][]LineDTO
+	Attrs     map[string]string
+	Extra     map[string]any
+	ByRegion  map[string]AddressDTO
+	Counts    map[int32]*int32
+	CreatedAt time.Time
+	Discount  string
+	Coupons   []string
+	Internal  string
+	ignored   string
+}
+
+// Order is the destination counterpart of OrderDTO.
+type Order struct {
+	ID           int64
+	CustomerName string
+	Total        float64
+	Paid         *string
+	Notes        *string
+	Shipping     Address
+	Billing      *Address
+	Lines        []Line
+	Refs         []*Line
+	Tags         []string
+	Scores       []int64
+	Grid         [][]Line
+	Attrs        map[string]string
+	Extra        map[string]any
+	ByRegion     map[string]Address
+	Counts       map[int64]*int64
+	CreatedAt    time.Time
+	Discount     Money
+	Coupons      []Money
+	Unmatched    string
+	ignored      string
+}
@@ -0,0 +1,81 @@
+// Command mapper-gen generates reflection-free mapping functions that follow
+// the same rules as the runtime engine of github.com/tariklabs/mapper.
+//
+// Usage:
+//
+//	mapper-gen [-dir DIR] [-output FILE] [-tag NAME] Src:Dst [Src:Dst...]
+//
+// Each Src:Dst argument names a source and a destination struct type declared
+// in the package found in DIR. For every pair, mapper-gen writes an exported
+// function
+//
+//	func MapSrcToDst(dst *Dst, src *Src) error
+//
+// that applies name matching, tag aliases, mapconv conversions, deep copying of
+// slices and maps, and pointer/value flexibility exactly like [mapper.Map].
+// Nested struct pairs get unexported helper functions. The generated file
+// registers every exported function with [mapper.RegisterGenerated], so
+// mapper.Map and mapper.MapWithOptions dispatch to it when they are called
+// with the same type pair and tag name and otherwise default options.
+//
+// Type combinations the runtime engine can only reject with an error (for
+// example incompatible field types or an unsupported mapconv target) are
+// reported at generation time instead, as are interface source fields mapped
+// into concrete types, whose dynamic type is only known at run time.
+// Generated code does not enforce the maximum nesting depth, so mapper.Map
+// never dispatches to functions generated for recursive types, such as a
+// struct with a pointer to its own type. Called directly, they overflow the
+// stack on cyclic values.
+//
+// mapper-gen is designed for go:generate:
+//
+//	//go:generate go run github.com/tariklabs/mapper/cmd/mapper-gen -output user_mapper_gen.go UserDTO:User
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package declaring the types")
+	output := flag.String("output", "mapper_gen.go", "output file name, relative to -dir")
+	tag := flag.String("tag", "map", "struct tag used for field aliases")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: mapper-gen [-dir DIR] [-output FILE] [-tag NAME] Src:Dst [Src:Dst...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*dir, *output, *tag, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "mapper-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output, tag string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no type pairs given")
+	}
+
+	pairs := make([]typePair, 0, len(args))
+	for _, arg := range args {
+		for _, spec := range strings.Split(arg, ",") {
+			src, dst, ok := strings.Cut(spec, ":")
+			if !ok || src == "" || dst == "" {
+				return fmt.Errorf("invalid type pair %q, want Src:Dst", spec)
+			}
+			pairs = append(pairs, typePair{src: src, dst: dst})
+		}
+	}
+
+	code, err := generate(dir, tag, pairs)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), code, 0o644)
+}
@@ -0,0 +1,86 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// contextCheckInterval is the number of slice elements or map entries mapped
+// between two checks of the call's context.
+const contextCheckInterval = 256
+
+// MapContext copies fields from src to dst like [MapWithOptions], under the
+// control of ctx.
+//
+// The context is checked before mapping starts and periodically while slices
+// and maps are mapped, so large collections stop early once ctx is canceled
+// or its deadline passes. Cancellation is returned as a [*MappingError] with
+// code [CodeCanceled] that wraps ctx.Err(), at the path of the collection
+// being mapped; it stops the call even with [WithCollectErrors]. dst may be
+// partially mapped when it is returned. Functions registered with
+// [RegisterGenerated] can neither be canceled nor pass ctx on, so they are
+// bypassed unless ctx is [context.Background] or [context.TODO].
+//
+// The context is also passed to converters added with [WithContextConverter]
+// or [RegisterContextConverter], and to the context-aware hooks and
+// self-mapping methods, such as [BeforeMapperContext] and [MapFromerContext],
+// which can read request-scoped values such as the tenant, locale or
+// permissions of the caller:
+//
+//	err := mapper.MapContext(r.Context(), &resp, order,
+//	    mapper.WithContextConverter(func(ctx context.Context, p Money) (string, error) {
+//	        return formatMoney(p, localeFrom(ctx)), nil
+//	    }),
+//	)
+func MapContext(ctx context.Context, dst, src any, opts ...Option) error {
+	return defaultMapper.MapContext(ctx, dst, src, opts...)
+}
+
+// MapContext copies fields from src to dst under the control of ctx, using
+// the options the Mapper was created with, followed by opts. See [MapContext].
+func (m *Mapper) MapContext(ctx context.Context, dst, src any, opts ...Option) error {
+	cfg := m.callConfig(opts)
+	cfg.ctx = ctx
+	return runMapping(dst, src, &cfg)
+}
+
+// context returns the context of the call, or context.Background when the
+// call has none.
+func (c *config) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// canceled reports whether the context of the call is done.
+func (c *config) canceled() bool {
+	return c.ctx != nil && c.ctx.Err() != nil
+}
+
+// hasContext reports whether the call has a context other than
+// context.Background or context.TODO, which may be canceled or carry values.
+func (c *config) hasContext() bool {
+	return c.ctx != nil && c.ctx != context.Background() && c.ctx != context.TODO()
+}
+
+// checkContext returns a cancellation error once the call's context is done.
+// Loops call it with their iteration index and only check every
+// contextCheckInterval iterations, and return the error through recordError
+// so the errors they collected before it are kept.
+func (c *config) checkContext(i int, srcStructType, dstStructType reflect.Type, fieldPath string) error {
+	if c.ctx == nil || i%contextCheckInterval != 0 {
+		return nil
+	}
+	if err := c.ctx.Err(); err != nil {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    "mapping canceled: " + err.Error(),
+			Code:      CodeCanceled,
+			Err:       err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package mapper
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type ctxItem struct {
+	ID int
+}
+
+type ctxItemDTO struct {
+	ID string
+}
+
+type ctxBatch struct {
+	Name  string
+	Items []ctxItem
+	Index map[string]ctxItem
+}
+
+type ctxBatchDTO struct {
+	Name  string
+	Items []ctxItemDTO
+	Index map[string]ctxItemDTO
+}
+
+type ctxTenantKey struct{}
+
+// cancelAfter returns a converter that cancels the context after n calls.
+func cancelAfter(n int, cancel context.CancelFunc, calls *int) func(context.Context, ctxItem) (ctxItemDTO, error) {
+	return func(ctx context.Context, it ctxItem) (ctxItemDTO, error) {
+		*calls++
+		if *calls == n {
+			cancel()
+		}
+		return ctxItemDTO{ID: strconv.Itoa(it.ID)}, nil
+	}
+}
+
+func TestMapContext_CanceledBeforeMapping(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := ctxBatch{Name: "batch", Items: []ctxItem{{ID: 0}, {ID: 1}, {ID: 2}}}
+	var dst ctxBatchDTO
+	err := MapContext(ctx, &dst, src)
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.Code != CodeCanceled || mappingErr.FieldPath != "" {
+		t.Fatalf("expected canceled error at the root, got %v", err)
+	}
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to match ErrCanceled and context.Canceled, got %v", err)
+	}
+	if dst.Name != "" {
+		t.Errorf("expected dst to be untouched, got %+v", dst)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if err := MapContext(ctx, &dst, src); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+}
+
+func TestMapContext_CanceledDuringSlice(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src := ctxBatch{Name: "batch", Items: make([]ctxItem, 2000)}
+	for i := range src.Items {
+		src.Items[i] = ctxItem{ID: i}
+	}
+
+	var calls int
+	var dst ctxBatchDTO
+	err := MapContext(ctx, &dst, src, WithContextConverter(cancelAfter(300, cancel, &calls)))
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.Code != CodeCanceled || mappingErr.FieldPath != "Items" {
+		t.Fatalf("expected canceled error at Items, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to wrap context.Canceled, got %v", err)
+	}
+	if calls >= len(src.Items) {
+		t.Errorf("expected mapping to stop early, converted %d items", calls)
+	}
+}
+
+func TestMapContext_CanceledDuringMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src := ctxBatch{Name: "batch", Index: make(map[string]ctxItem, 2000)}
+	for i := 0; i < 2000; i++ {
+		src.Index[strconv.Itoa(i)] = ctxItem{ID: i}
+	}
+
+	var calls int
+	var dst ctxBatchDTO
+	err := MapContext(ctx, &dst, src, WithContextConverter(cancelAfter(300, cancel, &calls)))
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.Code != CodeCanceled || mappingErr.FieldPath != "Index" {
+		t.Fatalf("expected canceled error at Index, got %v", err)
+	}
+	if calls >= len(src.Index) {
+		t.Errorf("expected mapping to stop early, converted %d entries", calls)
+	}
+}
+
+func TestMapContext_StopsCollectingErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type Src struct {
+		First  ctxBatch
+		Second ctxBatch
+	}
+	type Dst struct {
+		First  ctxBatchDTO
+		Second ctxBatchDTO
+	}
+
+	items := make([]ctxItem, 1000)
+	src := Src{
+		First:  ctxBatch{Name: "first", Items: items},
+		Second: ctxBatch{Name: "second", Items: items},
+	}
+
+	var calls int
+	var dst Dst
+	err := MapContext(ctx, &dst, src,
+		WithCollectErrors(),
+		WithContextConverter(cancelAfter(10, cancel, &calls)),
+	)
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].FieldPath != "First.Items" {
+		t.Fatalf("expected a single canceled error at First.Items, got %v", err)
+	}
+	if !errors.Is(err, ErrCanceled) {
+		t.Errorf("expected error to match ErrCanceled, got %v", err)
+	}
+	if dst.Second.Name != "" {
+		t.Error("expected mapping to stop after cancellation")
+	}
+}
+
+func TestMapContext_KeepsCollectedErrors(t *testing.T) {
+	for _, name := range []string{"Items", "Index"} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			src := ctxBatch{Name: "batch"}
+			if name == "Items" {
+				src.Items = make([]ctxItem, 1000)
+				for i := range src.Items {
+					src.Items[i] = ctxItem{ID: i}
+				}
+			} else {
+				src.Index = make(map[string]ctxItem, 1000)
+				for i := 0; i < 1000; i++ {
+					src.Index[strconv.Itoa(i)] = ctxItem{ID: i}
+				}
+			}
+
+			var calls int
+			conv := func(ctx context.Context, it ctxItem) (ctxItemDTO, error) {
+				if calls++; calls == 300 {
+					cancel()
+				}
+				if calls <= 2 {
+					return ctxItemDTO{}, errors.New("invalid item")
+				}
+				return ctxItemDTO{ID: strconv.Itoa(it.ID)}, nil
+			}
+
+			var dst ctxBatchDTO
+			err := MapContext(ctx, &dst, src, WithCollectErrors(), WithContextConverter(conv))
+
+			var errs MappingErrors
+			if !errors.As(err, &errs) || len(errs) != 3 {
+				t.Fatalf("expected two converter errors and the cancellation, got %v", err)
+			}
+			if errs[0].Code != CodeConverterFailed || errs[1].Code != CodeConverterFailed {
+				t.Errorf("expected the converter errors to be kept, got %v", errs)
+			}
+			if errs[2].Code != CodeCanceled || errs[2].FieldPath != name {
+				t.Errorf("expected the cancellation at %s last, got %v", name, errs[2])
+			}
+		})
+	}
+}
+
+func TestMapContext_ConverterReceivesContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxTenantKey{}, "acme")
+	prefix := WithContextConverter(func(ctx context.Context, it ctxItem) (ctxItemDTO, error) {
+		tenant, _ := ctx.Value(ctxTenantKey{}).(string)
+		return ctxItemDTO{ID: tenant + "/" + strconv.Itoa(it.ID)}, nil
+	})
+
+	var dst ctxBatchDTO
+	src := ctxBatch{Items: []ctxItem{{ID: 0}, {ID: 1}}, Index: map[string]ctxItem{"0": {ID: 0}}}
+	if err := MapContext(ctx, &dst, src, prefix); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Items[1].ID != "acme/1" || dst.Index["0"].ID != "acme/0" {
+		t.Errorf("expected converter to read the context, got %+v", dst)
+	}
+
+	// Calls without a context pass context.Background
+	dst = ctxBatchDTO{}
+	if err := MapWithOptions(&dst, ctxBatch{Items: []ctxItem{{ID: 0}}}, prefix); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Items[0].ID != "/0" {
+		t.Errorf("expected an empty tenant, got %q", dst.Items[0].ID)
+	}
+}
+
+func TestRegisterContextConverter(t *testing.T) {
+	RegisterContextConverter(func(ctx context.Context, it ctxItem) (ctxItemDTO, error) {
+		tenant, _ := ctx.Value(ctxTenantKey{}).(string)
+		return ctxItemDTO{ID: tenant}, nil
+	})
+	t.Cleanup(func() {
+		registeredConverters.delete(converterKey{src: typeFor[ctxItem](), dst: typeFor[ctxItemDTO]()})
+	})
+
+	ctx := context.WithValue(context.Background(), ctxTenantKey{}, "acme")
+	var dst ctxBatchDTO
+	if err := MapContext(ctx, &dst, ctxBatch{Items: []ctxItem{{ID: 1}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Items[0].ID != "acme" {
+		t.Errorf("expected registered converter to read the context, got %q", dst.Items[0].ID)
+	}
+}
+
+// canceledAfterStart is a canceled context that reports it only after the
+// check made before mapping starts.
+type canceledAfterStart struct {
+	context.Context
+	checks int
+}
+
+func (c *canceledAfterStart) Err() error {
+	if c.checks++; c.checks == 1 {
+		return nil
+	}
+	return c.Context.Err()
+}
+
+func TestMapContext_BypassesGeneratedMappers(t *testing.T) {
+	var generated bool
+	RegisterGenerated(func(dst *ctxBatch, src *ctxBatch) error {
+		generated = true
+		*dst = *src
+		return nil
+	})
+	t.Cleanup(func() {
+		generatedMappers.Delete(generatedKey{src: typeFor[ctxBatch](), dst: typeFor[ctxBatch](), tagName: "map"})
+		generatedCount.Add(-1)
+	})
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var dst ctxBatch
+	err := MapContext(&canceledAfterStart{Context: parent}, &dst, ctxBatch{Items: make([]ctxItem, 100000)})
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the engine to stop at the first collection, got %v", err)
+	}
+	if generated {
+		t.Error("expected generated code, which cannot be canceled, to be bypassed")
+	}
+
+	// Contexts carrying values are passed on, which generated code cannot do
+	type key struct{}
+	if err := MapContext(context.WithValue(context.Background(), key{}, 1), &dst, ctxBatch{Name: "batch"}); err != nil || generated {
+		t.Errorf("expected the engine for a context with values, got %v", err)
+	}
+
+	// context.Background keeps the generated fast path
+	if err := MapContext(context.Background(), &dst, ctxBatch{Name: "batch"}); err != nil || !generated {
+		t.Errorf("expected the generated function with context.Background, got %v", err)
+	}
+}
@@ -5,109 +5,86 @@ import (
 	"strconv"
 )
 
-// convertString converts a string to the specified type.
-// Supported types: int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool.
-func convertString(str string, targetType string, srcStructType, dstStructType reflect.Type, fieldPath string) (reflect.Value, error) {
-	switch targetType {
-	case "int":
+// stringParser parses a string into a value of a mapconv target type.
+// The returned error is the raw parse error; callers wrap it with context.
+type stringParser func(str string) (reflect.Value, error)
+
+// stringParsers holds the parser for every supported mapconv target type.
+// Plans bind these once so the mapconv tag is not re-dispatched on every call.
+var stringParsers = map[string]stringParser{
+	"int": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseInt(str, 10, 64)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(int(val)), nil
-
-	case "int8":
+		return reflect.ValueOf(int(val)), err
+	},
+	"int8": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseInt(str, 10, 8)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(int8(val)), nil
-
-	case "int16":
+		return reflect.ValueOf(int8(val)), err
+	},
+	"int16": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseInt(str, 10, 16)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(int16(val)), nil
-
-	case "int32":
+		return reflect.ValueOf(int16(val)), err
+	},
+	"int32": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseInt(str, 10, 32)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(int32(val)), nil
-
-	case "int64":
+		return reflect.ValueOf(int32(val)), err
+	},
+	"int64": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseInt(str, 10, 64)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(val), nil
-
-	case "uint":
+		return reflect.ValueOf(val), err
+	},
+	"uint": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseUint(str, 10, 64)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(uint(val)), nil
-
-	case "uint8":
+		return reflect.ValueOf(uint(val)), err
+	},
+	"uint8": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseUint(str, 10, 8)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(uint8(val)), nil
-
-	case "uint16":
+		return reflect.ValueOf(uint8(val)), err
+	},
+	"uint16": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseUint(str, 10, 16)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(uint16(val)), nil
-
-	case "uint32":
+		return reflect.ValueOf(uint16(val)), err
+	},
+	"uint32": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseUint(str, 10, 32)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(uint32(val)), nil
-
-	case "uint64":
+		return reflect.ValueOf(uint32(val)), err
+	},
+	"uint64": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseUint(str, 10, 64)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(val), nil
-
-	case "float32":
+		return reflect.ValueOf(val), err
+	},
+	"float32": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseFloat(str, 32)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(float32(val)), nil
-
-	case "float64":
+		return reflect.ValueOf(float32(val)), err
+	},
+	"float64": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(val), nil
-
-	case "bool":
+		return reflect.ValueOf(val), err
+	},
+	"bool": func(str string) (reflect.Value, error) {
 		val, err := strconv.ParseBool(str)
-		if err != nil {
-			return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
-		}
-		return reflect.ValueOf(val), nil
+		return reflect.ValueOf(val), err
+	},
+}
 
-	default:
+// convertString converts a string to the specified type.
+// Supported types: int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool.
+func convertString(str string, targetType string, srcStructType, dstStructType reflect.Type, fieldPath string) (reflect.Value, error) {
+	parse, ok := stringParsers[targetType]
+	if !ok {
 		return reflect.Value{}, &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "unsupported mapconv target type: " + targetType,
+			Code:      CodeUnsupportedConversion,
 		}
 	}
+
+	val, err := parse(str)
+	if err != nil {
+		return reflect.Value{}, conversionError(str, targetType, err, srcStructType, dstStructType, fieldPath)
+	}
+	return val, nil
 }
 
 // conversionError creates a MappingError for string conversion failures.
@@ -117,5 +94,7 @@ func conversionError(str, targetType string, parseErr error, srcStructType, dstS
 		DstType:   dstStructType.String(),
 		FieldPath: fieldPath,
 		Reason:    "cannot convert \"" + str + "\" to " + targetType + ": " + parseErr.Error(),
+		Code:      CodeConversionFailed,
+		Err:       parseErr,
 	}
 }
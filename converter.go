@@ -0,0 +1,140 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// converterKey identifies a converter by its exact source and destination types.
+type converterKey struct {
+	src reflect.Type
+	dst reflect.Type
+}
+
+// converterFunc converts a source value into a value of the destination type.
+// ctx is the context of the mapping call.
+type converterFunc func(ctx context.Context, src reflect.Value) (reflect.Value, error)
+
+// RegisterConverter registers a function that converts values of type S into
+// values of type D. Registered converters are used by every mapping call made
+// through [Map] and [MapWithOptions]; [Mapper] instances created with [New]
+// take converters through [WithConverter] instead.
+//
+// Converters are consulted before the built-in kind-based rules whenever a
+// value of exactly type S is assigned to a destination of exactly type D:
+// struct fields at any depth, slice elements, map values and pointer elements.
+// Explicit mapconv tags take precedence over converters.
+//
+// Example:
+//
+//	mapper.RegisterConverter(func(id uuid.UUID) (string, error) {
+//	    return id.String(), nil
+//	})
+//
+//	mapper.RegisterConverter(func(m Money) (MoneyDTO, error) {
+//	    return MoneyDTO{Amount: m.Amount.String(), Currency: m.Currency}, nil
+//	})
+//
+// A converter error is returned as a [*MappingError] with the path of the
+// field being converted. Registering a converter for a type pair that already
+// has one replaces it. RegisterConverter is safe for concurrent use, but is
+// typically called during program initialization.
+func RegisterConverter[S, D any](fn func(S) (D, error)) {
+	key, conv := newConverter(fn)
+	registeredConverters.store(key, conv)
+}
+
+// RegisterContextConverter is like [RegisterConverter] for a converter that
+// also receives the context of the mapping call: the ctx passed to
+// [MapContext], or context.Background for calls made without one.
+//
+// Example:
+//
+//	mapper.RegisterContextConverter(func(ctx context.Context, t time.Time) (string, error) {
+//	    return t.In(userLocation(ctx)).Format(time.RFC3339), nil
+//	})
+func RegisterContextConverter[S, D any](fn func(context.Context, S) (D, error)) {
+	key, conv := newContextConverter(fn)
+	registeredConverters.store(key, conv)
+}
+
+// WithConverter adds a converter from S to D for a single mapping call, or for
+// every call of a [Mapper] when passed to [New]. It takes precedence over a
+// converter registered for the same type pair with [RegisterConverter].
+//
+// Example:
+//
+//	err := mapper.MapWithOptions(&dst, src,
+//	    mapper.WithConverter(func(d decimal.Decimal) (float64, error) {
+//	        f, _ := d.Float64()
+//	        return f, nil
+//	    }),
+//	)
+func WithConverter[S, D any](fn func(S) (D, error)) Option {
+	key, conv := newConverter(fn)
+	return withConverter(key, conv)
+}
+
+// WithContextConverter is like [WithConverter] for a converter that also
+// receives the context of the mapping call; see [RegisterContextConverter].
+func WithContextConverter[S, D any](fn func(context.Context, S) (D, error)) Option {
+	key, conv := newContextConverter(fn)
+	return withConverter(key, conv)
+}
+
+func withConverter(key converterKey, conv converterFunc) Option {
+	return func(c *config) {
+		c.converters = copyMap(c.converters, 1)
+		c.converters[key] = conv
+	}
+}
+
+func newConverter[S, D any](fn func(S) (D, error)) (converterKey, converterFunc) {
+	return newContextConverter(func(_ context.Context, s S) (D, error) {
+		return fn(s)
+	})
+}
+
+func newContextConverter[S, D any](fn func(context.Context, S) (D, error)) (converterKey, converterFunc) {
+	key := converterKey{src: typeFor[S](), dst: typeFor[D]()}
+	conv := func(ctx context.Context, src reflect.Value) (reflect.Value, error) {
+		// A nil interface value yields the zero S
+		s, _ := src.Interface().(S)
+		d, err := fn(ctx, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&d).Elem(), nil
+	}
+	return key, conv
+}
+
+// hasConverters reports whether any converter may apply to this call.
+// It keeps converter lookups off the hot path when none are registered.
+func (c *config) hasConverters() bool {
+	return hasEntries(c.converters, &registeredConverters, c.registered)
+}
+
+// lookupConverter returns the converter for the exact type pair, preferring
+// per-call converters over registered ones.
+func (c *config) lookupConverter(srcType, dstType reflect.Type) (converterFunc, bool) {
+	key := converterKey{src: srcType, dst: dstType}
+	return lookupEntry(c.converters, &registeredConverters, c.registered, key)
+}
+
+// applyConverter converts src with conv and stores the result in dst.
+func applyConverter(conv converterFunc, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config) error {
+	converted, err := conv(cfg.context(), src)
+	if err != nil {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    "converter " + src.Type().String() + " -> " + dst.Type().String() + " failed: " + err.Error(),
+			Code:      CodeConverterFailed,
+			Err:       err,
+		}
+	}
+	dst.Set(converted)
+	return nil
+}
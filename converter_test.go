@@ -0,0 +1,252 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type convMoney struct {
+	Cents    int64
+	Currency string
+}
+
+type convMoneyDTO struct {
+	Amount string
+}
+
+func registerTestConverter[S, D any](t *testing.T, fn func(S) (D, error)) {
+	t.Helper()
+	RegisterConverter(fn)
+	t.Cleanup(func() {
+		registeredConverters.delete(converterKey{src: typeFor[S](), dst: typeFor[D]()})
+	})
+}
+
+func formatMoney(m convMoney) (convMoneyDTO, error) {
+	if m.Currency == "" {
+		return convMoneyDTO{}, errors.New("missing currency")
+	}
+	return convMoneyDTO{Amount: fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)}, nil
+}
+
+func TestConverter_AllNestingLevels(t *testing.T) {
+	registerTestConverter(t, formatMoney)
+
+	type Inner struct {
+		Price convMoney
+	}
+	type InnerDTO struct {
+		Price convMoneyDTO
+	}
+	type Src struct {
+		Total  convMoney
+		Inner  Inner
+		Items  []convMoney
+		ByCode map[string]convMoney
+		Refund *convMoney
+	}
+	type Dst struct {
+		Total  convMoneyDTO
+		Inner  InnerDTO
+		Items  []convMoneyDTO
+		ByCode map[string]convMoneyDTO
+		Refund *convMoneyDTO
+	}
+
+	src := Src{
+		Total:  convMoney{Cents: 1250, Currency: "EUR"},
+		Inner:  Inner{Price: convMoney{Cents: 99, Currency: "USD"}},
+		Items:  []convMoney{{Cents: 100, Currency: "EUR"}, {Cents: 205, Currency: "EUR"}},
+		ByCode: map[string]convMoney{"a": {Cents: 1, Currency: "GBP"}},
+		Refund: &convMoney{Cents: 500, Currency: "EUR"},
+	}
+
+	var dst Dst
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Total.Amount != "12.50 EUR" {
+		t.Errorf("Total: expected '12.50 EUR', got %q", dst.Total.Amount)
+	}
+	if dst.Inner.Price.Amount != "0.99 USD" {
+		t.Errorf("Inner.Price: expected '0.99 USD', got %q", dst.Inner.Price.Amount)
+	}
+	if len(dst.Items) != 2 || dst.Items[1].Amount != "2.05 EUR" {
+		t.Errorf("Items: unexpected result %+v", dst.Items)
+	}
+	if dst.ByCode["a"].Amount != "0.01 GBP" {
+		t.Errorf("ByCode: unexpected result %+v", dst.ByCode)
+	}
+	if dst.Refund == nil || dst.Refund.Amount != "5.00 EUR" {
+		t.Errorf("Refund: unexpected result %+v", dst.Refund)
+	}
+}
+
+func TestConverter_ErrorPaths(t *testing.T) {
+	registerTestConverter(t, formatMoney)
+
+	type Inner struct {
+		Price convMoney
+	}
+	type InnerDTO struct {
+		Price convMoneyDTO
+	}
+
+	tests := []struct {
+		name     string
+		run      func() error
+		wantPath string
+	}{
+		{
+			name: "nested field",
+			run: func() error {
+				type Src struct{ Inner Inner }
+				type Dst struct{ Inner InnerDTO }
+				var dst Dst
+				return Map(&dst, Src{})
+			},
+			wantPath: "Inner.Price",
+		},
+		{
+			name: "slice element",
+			run: func() error {
+				type Src struct{ Items []convMoney }
+				type Dst struct{ Items []convMoneyDTO }
+				var dst Dst
+				items := []convMoney{{Currency: "EUR"}, {Currency: "EUR"}, {Currency: "EUR"}, {}}
+				return Map(&dst, Src{Items: items})
+			},
+			wantPath: "Items[3]",
+		},
+		{
+			name: "map value",
+			run: func() error {
+				type Src struct{ Config map[string]convMoney }
+				type Dst struct{ Config map[string]convMoneyDTO }
+				var dst Dst
+				return Map(&dst, Src{Config: map[string]convMoney{"key": {}}})
+			},
+			wantPath: "Config[key]",
+		},
+		{
+			name: "pointer element",
+			run: func() error {
+				type Src struct{ Refund *convMoney }
+				type Dst struct{ Refund *convMoneyDTO }
+				var dst Dst
+				return Map(&dst, Src{Refund: &convMoney{}})
+			},
+			wantPath: "Refund",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			mappingErr, ok := err.(*MappingError)
+			if !ok {
+				t.Fatalf("expected *MappingError, got %T", err)
+			}
+			if mappingErr.FieldPath != tt.wantPath {
+				t.Errorf("expected FieldPath %q, got %q", tt.wantPath, mappingErr.FieldPath)
+			}
+			if !strings.Contains(mappingErr.Reason, "missing currency") {
+				t.Errorf("expected reason to contain converter error, got %q", mappingErr.Reason)
+			}
+		})
+	}
+}
+
+func TestConverter_PerCallOverridesRegistered(t *testing.T) {
+	registerTestConverter(t, formatMoney)
+
+	type Src struct{ Total convMoney }
+	type Dst struct{ Total convMoneyDTO }
+
+	var dst Dst
+	err := MapWithOptions(&dst, Src{Total: convMoney{Cents: 700, Currency: "EUR"}},
+		WithConverter(func(m convMoney) (convMoneyDTO, error) {
+			return convMoneyDTO{Amount: fmt.Sprintf("%d", m.Cents)}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Total.Amount != "700" {
+		t.Errorf("expected per-call converter to win, got %q", dst.Total.Amount)
+	}
+
+	// The per-call converter must not leak into later calls
+	dst = Dst{}
+	if err := Map(&dst, Src{Total: convMoney{Cents: 700, Currency: "EUR"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Total.Amount != "7.00 EUR" {
+		t.Errorf("expected registered converter, got %q", dst.Total.Amount)
+	}
+}
+
+func TestConverter_OverridesBuiltInRules(t *testing.T) {
+	type Src struct {
+		Name  string
+		Count int
+	}
+
+	var dst Src
+	err := MapWithOptions(&dst, Src{Name: "  alice ", Count: 3},
+		WithConverter(func(s string) (string, error) {
+			return strings.TrimSpace(s), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "alice" || dst.Count != 3 {
+		t.Errorf("expected converter to apply to identical types, got %+v", dst)
+	}
+}
+
+func TestConverter_MapconvTakesPrecedence(t *testing.T) {
+	type Src struct {
+		Age string `mapconv:"int"`
+	}
+	type Dst struct {
+		Age int
+	}
+
+	var dst Dst
+	err := MapWithOptions(&dst, Src{Age: "42"},
+		WithConverter(func(s string) (int, error) {
+			return -1, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Age != 42 {
+		t.Errorf("expected mapconv to take precedence, got %d", dst.Age)
+	}
+}
+
+func TestConverter_DisablesGeneratedDispatch(t *testing.T) {
+	registerGenTestMapper(t)
+
+	var dst genDst
+	err := MapWithOptions(&dst, genSrc{Name: "alice"},
+		WithConverter(func(s string) (string, error) {
+			return strings.ToUpper(s), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via == "generated" || dst.Name != "ALICE" {
+		t.Errorf("expected the engine to apply the converter, got %+v", dst)
+	}
+}
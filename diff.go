@@ -0,0 +1,484 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind classifies a [Change].
+type ChangeKind uint8
+
+const (
+	// ChangeModified reports a value that differs between both sides.
+	ChangeModified ChangeKind = iota
+	// ChangeAdded reports a value that is only present on the new side: a
+	// nil pointer that became non-nil, a new slice element or a new map key.
+	ChangeAdded
+	// ChangeRemoved reports a value that is only present on the old side.
+	ChangeRemoved
+)
+
+// String returns "modified", "added" or "removed".
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// Change is a single difference reported by [Diff].
+type Change struct {
+	// Path locates the value in the old struct using the same notation as
+	// [MappingError], such as "Address.City", "Items[2]" or "Labels[team]".
+	Path string
+	Kind ChangeKind
+	// Old is the old value; it is nil for added values.
+	Old any
+	// New is the new value converted to the type of the old side; it is nil
+	// for removed values.
+	New any
+}
+
+// Diff compares the structs a (old) and b (new) and returns their
+// differences in field declaration order. Each argument may be a struct or a
+// non-nil pointer to one.
+//
+// Fields are matched the way [Map] would map b into a: by name or by the
+// configured tag name, so a DTO can be compared with the entity it maps to.
+// Fields of a without a matching field in b, and fields of b that map to no
+// field of a, are not compared. Before comparison, values of b are converted
+// to the type of the matching field of a with the same rules as mapping,
+// including mapconv tags and converters; the converted value is reported as
+// [Change.New].
+//
+// Nested structs and pointers are compared field by field, slices by index
+// and maps by key. Structs without exported fields, such as time.Time, are
+// compared as a whole with their Equal method when they have one and with
+// reflect.DeepEqual otherwise. An absent [Optional] in b is not compared.
+//
+// Example:
+//
+//	changes, err := mapper.Diff(user, req, mapper.WithTagName("json"))
+//	for _, c := range changes {
+//	    fmt.Println(c.Kind, c.Path, c.Old, "->", c.New)
+//	}
+//	// modified Name Alice -> Alicia
+//	// added Tags[2] <nil> -> admin
+//
+// [WithIgnoreZeroSource] skips zero fields of b, [WithMaxDepth] limits the
+// nesting depth and [WithCollectErrors] collects conversion errors, in which
+// case the changes found are returned along with the errors.
+func Diff(a, b any, opts ...Option) ([]Change, error) {
+	return defaultMapper.Diff(a, b, opts...)
+}
+
+// Diff compares a and b using the options the Mapper was created with,
+// followed by opts. See [Diff].
+func (m *Mapper) Diff(a, b any, opts ...Option) ([]Change, error) {
+	cfg := m.callConfig(opts)
+	return runDiff(a, b, &cfg)
+}
+
+// differ accumulates the changes found by one Diff or CreatePatch call.
+type differ struct {
+	cfg     *config
+	oldVal  reflect.Value // root structs
+	newVal  reflect.Value
+	oldType reflect.Type
+	newType reflect.Type
+	// pointer is set by CreatePatch: locations are also tracked as JSON
+	// Pointers and changes are recorded as patch operations.
+	pointer bool
+	changes []Change
+	ops     Patch
+}
+
+func runDiff(a, b any, cfg *config) ([]Change, error) {
+	d, err := newDiffer(a, b, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.run(); err != nil {
+		return d.changes, err
+	}
+	return d.changes, nil
+}
+
+func newDiffer(a, b any, cfg *config) (*differ, error) {
+	oldVal, err := diffInput(a, a, b)
+	if err != nil {
+		return nil, err
+	}
+	newVal, err := diffInput(b, a, b)
+	if err != nil {
+		return nil, err
+	}
+	return &differ{cfg: cfg, oldVal: oldVal, newVal: newVal, oldType: oldVal.Type(), newType: newVal.Type()}, nil
+}
+
+// run compares the root structs. Without WithCollectErrors the first error
+// discards the changes found so far.
+func (d *differ) run() error {
+	err := d.diffStruct(d.oldVal, d.newVal, "", "", d.cfg.maxDepth)
+	if err != nil {
+		if _, collected := err.(MappingErrors); !collected {
+			d.changes, d.ops = nil, nil
+		}
+	}
+	return err
+}
+
+// diffInput returns the struct v holds or points to.
+func diffInput(v, a, b any) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Value{}, &MappingError{
+			SrcType:   typeOf(b),
+			DstType:   typeOf(a),
+			FieldPath: "",
+			Reason:    "nil value to compare",
+			Code:      CodeNilInput,
+		}
+	}
+
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return reflect.Value{}, &MappingError{
+				SrcType:   typeOf(b),
+				DstType:   typeOf(a),
+				FieldPath: "",
+				Reason:    "value to compare is a nil pointer",
+				Code:      CodeNilInput,
+			}
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, &MappingError{
+			SrcType:   typeOf(b),
+			DstType:   typeOf(a),
+			FieldPath: "",
+			Reason:    "values to compare must be structs or pointers to structs",
+			Code:      CodeInvalidInput,
+		}
+	}
+	return val, nil
+}
+
+// record adds a change at path, or at the JSON Pointer ptr when building a
+// patch. inPlace is set when the location itself is kept on both sides, as
+// for a pointer that becomes nil or non-nil; such changes are replacements.
+func (d *differ) record(kind ChangeKind, path, ptr string, inPlace bool, oldVal, newVal any) {
+	if !d.pointer {
+		d.changes = append(d.changes, Change{Path: path, Kind: kind, Old: oldVal, New: newVal})
+		return
+	}
+
+	op := PatchOp{Op: "replace", Path: ptr, Value: newVal, old: oldVal}
+	if !inPlace {
+		switch kind {
+		case ChangeAdded:
+			op.Op = "add"
+		case ChangeRemoved:
+			op.Op = "remove"
+		}
+	}
+	d.ops = append(d.ops, op)
+}
+
+// diffStruct compares the fields of b that map to fields of a.
+func (d *differ) diffStruct(oldVal, newVal reflect.Value, path, ptr string, depth int) error {
+	if depth <= 0 {
+		return d.depthError(path)
+	}
+
+	plan, err := d.cfg.mapper.getStructPlan(newVal.Type(), oldVal.Type(), d.cfg.tagName)
+	if err != nil {
+		return err
+	}
+
+	var tokens map[int]string
+	if d.pointer {
+		if tokens, err = d.pointerTokens(oldVal.Type()); err != nil {
+			return err
+		}
+	}
+
+	var errs MappingErrors
+	for i := range plan.fields {
+		fp := &plan.fields[i]
+		if fp.unmatched {
+			continue
+		}
+
+		var fieldPtr string
+		if d.pointer {
+			token, ok := tokens[fp.dstIndex]
+			if !ok {
+				continue
+			}
+			fieldPtr = ptr + "/" + token
+		}
+
+		newField := newVal.Field(fp.srcIndex)
+		if d.cfg.ignoreZeroSource && newField.IsZero() {
+			continue
+		}
+
+		if err := d.diffValue(oldVal.Field(fp.dstIndex), newField, buildPath(path, fp.name), fieldPtr, fp.convertTo, depth); err != nil {
+			if err = d.cfg.recordError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.orNil()
+}
+
+// diffValue compares oldVal with newVal, whose type may differ.
+func (d *differ) diffValue(oldVal, newVal reflect.Value, path, ptr, convertTo string, depth int) error {
+	if depth <= 0 {
+		return d.depthError(path)
+	}
+
+	newType := newVal.Type()
+	if info := optionalOf(newType); info != nil && newType != oldVal.Type() {
+		value, present, set := readOptional(newVal, info)
+		if !present {
+			return nil
+		}
+		if !set {
+			value = reflect.Zero(oldVal.Type())
+		}
+		newVal = value
+	}
+
+	oldPtr := oldVal.Kind() == reflect.Ptr
+	newPtr := newVal.Kind() == reflect.Ptr
+	if oldPtr || newPtr {
+		oldNil := oldPtr && oldVal.IsNil()
+		newNil := newPtr && newVal.IsNil()
+		switch {
+		case oldNil && newNil:
+			return nil
+		case oldNil:
+			return d.added(oldVal.Type(), newVal, path, ptr, true, convertTo, depth)
+		case newNil:
+			d.record(ChangeRemoved, path, ptr, true, oldVal.Interface(), nil)
+			return nil
+		}
+		if oldPtr {
+			oldVal = oldVal.Elem()
+		}
+		if newPtr {
+			newVal = newVal.Elem()
+		}
+		return d.diffValue(oldVal, newVal, path, ptr, convertTo, depth-1)
+	}
+
+	oldKind := oldVal.Kind()
+	newKind := newVal.Kind()
+
+	// A JSON Patch cannot add elements to a null array or object, so a
+	// collection that becomes nil or non-nil is replaced as a whole.
+	nilChanged := d.pointer && isNilCollection(oldVal) != isNilCollection(newVal)
+
+	switch {
+	case oldKind == reflect.Struct && newKind == reflect.Struct && !d.wholeValue(oldVal.Type(), newVal.Type()):
+		return d.diffStruct(oldVal, newVal, path, ptr, depth-1)
+	case isSequenceKind(oldKind) && isSequenceKind(newKind) && !nilChanged:
+		return d.diffSequence(oldVal, newVal, path, ptr, depth-1)
+	case oldKind == reflect.Map && newKind == reflect.Map && !nilChanged:
+		return d.diffMap(oldVal, newVal, path, ptr, depth-1)
+	}
+
+	converted, err := d.convert(newVal, oldVal.Type(), path, convertTo, depth)
+	if err != nil {
+		return err
+	}
+	if !valuesEqual(oldVal, converted) {
+		d.record(ChangeModified, path, ptr, true, oldVal.Interface(), converted.Interface())
+	}
+	return nil
+}
+
+// diffSequence compares slices or arrays by index. In a patch, trailing
+// elements are removed from the last one so earlier indexes stay valid.
+func (d *differ) diffSequence(oldVal, newVal reflect.Value, path, ptr string, depth int) error {
+	if depth <= 0 {
+		return d.depthError(path)
+	}
+
+	elemType := oldVal.Type().Elem()
+	for i := 0; i < oldVal.Len() && i < newVal.Len(); i++ {
+		if err := d.diffValue(oldVal.Index(i), newVal.Index(i), buildSlicePath(path, i), d.indexPointer(ptr, i), "", depth); err != nil {
+			return err
+		}
+	}
+	for i := oldVal.Len(); i < newVal.Len(); i++ {
+		if err := d.added(elemType, newVal.Index(i), buildSlicePath(path, i), d.indexPointer(ptr, i), false, "", depth); err != nil {
+			return err
+		}
+	}
+	for i := newVal.Len(); i < oldVal.Len(); i++ {
+		j := i
+		if d.pointer {
+			j = oldVal.Len() - 1 - (i - newVal.Len())
+		}
+		d.record(ChangeRemoved, buildSlicePath(path, j), d.indexPointer(ptr, j), false, oldVal.Index(j).Interface(), nil)
+	}
+	return nil
+}
+
+// diffMap compares maps by key, after converting the keys of newVal to the
+// key type of oldVal. Keys are visited in sorted order.
+func (d *differ) diffMap(oldVal, newVal reflect.Value, path, ptr string, depth int) error {
+	if depth <= 0 {
+		return d.depthError(path)
+	}
+
+	oldType := oldVal.Type()
+	newEntries := make(map[any]reflect.Value, newVal.Len())
+	keys := oldVal.MapKeys()
+
+	iter := newVal.MapRange()
+	for iter.Next() {
+		key, err := d.convert(iter.Key(), oldType.Key(), path, "", depth)
+		if err != nil {
+			return err
+		}
+		newEntries[key.Interface()] = iter.Value()
+		if !oldVal.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sortMapKeys(keys)
+
+	for _, key := range keys {
+		keyPath := buildMapPath(path, key)
+		var keyPtr string
+		if d.pointer {
+			keyPtr = ptr + "/" + escapePointerToken(formatMapKey(key))
+		}
+		oldEntry := oldVal.MapIndex(key)
+		newEntry, inNew := newEntries[key.Interface()]
+
+		var err error
+		switch {
+		case !oldEntry.IsValid():
+			err = d.added(oldType.Elem(), newEntry, keyPath, keyPtr, false, "", depth)
+		case !inNew:
+			d.record(ChangeRemoved, keyPath, keyPtr, false, oldEntry.Interface(), nil)
+		default:
+			err = d.diffValue(oldEntry, newEntry, keyPath, keyPtr, "", depth)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// added records newVal, converted to t, as added at path.
+func (d *differ) added(t reflect.Type, newVal reflect.Value, path, ptr string, inPlace bool, convertTo string, depth int) error {
+	converted, err := d.convert(newVal, t, path, convertTo, depth)
+	if err != nil {
+		return err
+	}
+	d.record(ChangeAdded, path, ptr, inPlace, nil, converted.Interface())
+	return nil
+}
+
+// convert maps v into a new value of type t, as it would be mapped into a
+// destination field of that type.
+func (d *differ) convert(v reflect.Value, t reflect.Type, path, convertTo string, depth int) (reflect.Value, error) {
+	if v.Type() == t && convertTo == "" && !d.cfg.hasConverters() {
+		return v, nil
+	}
+	out := reflect.New(t).Elem()
+	if err := assignNestedValue(out, v, d.newType, d.oldType, path, "", convertTo, d.cfg, depth); err != nil {
+		return reflect.Value{}, err
+	}
+	return out, nil
+}
+
+// wholeValue reports whether structs of the given types are compared as a
+// whole rather than field by field.
+func (d *differ) wholeValue(oldType, newType reflect.Type) bool {
+	if oldType != newType {
+		return false
+	}
+	if _, ok := equalMethod(oldType); ok {
+		return true
+	}
+	meta, err := d.cfg.mapper.getStructMeta(oldType, d.cfg.tagName)
+	return err == nil && len(meta.Fields) == 0
+}
+
+func (d *differ) indexPointer(ptr string, index int) string {
+	if !d.pointer {
+		return ""
+	}
+	return ptr + "/" + strconv.Itoa(index)
+}
+
+func (d *differ) depthError(path string) error {
+	return &MappingError{
+		SrcType:   d.newType.String(),
+		DstType:   d.oldType.String(),
+		FieldPath: path,
+		Reason:    "maximum nesting depth exceeded (possible circular reference)",
+		Code:      CodeDepthExceeded,
+	}
+}
+
+// equalMethod returns the method index of an Equal(T) bool method on t.
+func equalMethod(t reflect.Type) (int, bool) {
+	m, ok := t.MethodByName("Equal")
+	if !ok || m.Type.NumIn() != 2 || m.Type.In(1) != t || m.Type.NumOut() != 1 || m.Type.Out(0).Kind() != reflect.Bool {
+		return 0, false
+	}
+	return m.Index, true
+}
+
+// valuesEqual compares two values of the same type, using the type's Equal
+// method when it has one.
+func valuesEqual(a, b reflect.Value) bool {
+	if i, ok := equalMethod(a.Type()); ok {
+		return a.Method(i).Call([]reflect.Value{b})[0].Bool()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func isSequenceKind(k reflect.Kind) bool {
+	return k == reflect.Slice || k == reflect.Array
+}
+
+func isNilCollection(v reflect.Value) bool {
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil()
+}
+
+// sortMapKeys sorts map keys numerically for numeric kinds and by their
+// formatted value otherwise.
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		default:
+			return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+		}
+	})
+}
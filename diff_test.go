@@ -0,0 +1,251 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diffAddress struct {
+	City string
+	Zip  string
+}
+
+type diffItem struct {
+	SKU string
+	Qty int
+}
+
+type diffUser struct {
+	Name    string
+	Age     int
+	Email   *string
+	Address *diffAddress
+	Home    diffAddress
+	Tags    []string
+	Items   []diffItem
+	Labels  map[string]string
+	Limits  map[int]int
+	Joined  time.Time
+}
+
+func TestDiff_SameType(t *testing.T) {
+	email := "alice@example.com"
+	joined := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := diffUser{
+		Name:    "Alice",
+		Age:     30,
+		Email:   &email,
+		Address: &diffAddress{City: "Paris", Zip: "75001"},
+		Home:    diffAddress{City: "Lyon", Zip: "69001"},
+		Tags:    []string{"a", "b"},
+		Items:   []diffItem{{SKU: "x", Qty: 1}},
+		Labels:  map[string]string{"team": "core", "tier": "gold"},
+		Limits:  map[int]int{2: 1, 10: 1},
+		Joined:  joined,
+	}
+	b := diffUser{
+		Name:    "Alicia",
+		Age:     30,
+		Address: &diffAddress{City: "Paris", Zip: "75002"},
+		Home:    diffAddress{City: "Nice", Zip: "69001"},
+		Tags:    []string{"a", "c", "d"},
+		Items:   []diffItem{{SKU: "x", Qty: 2}},
+		Labels:  map[string]string{"team": "core", "region": "eu"},
+		Limits:  map[int]int{2: 5, 10: 1},
+		Joined:  joined.In(time.FixedZone("CET", 3600)),
+	}
+
+	changes, err := Diff(a, &b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Change{
+		{Path: "Name", Kind: ChangeModified, Old: "Alice", New: "Alicia"},
+		{Path: "Email", Kind: ChangeRemoved, Old: a.Email},
+		{Path: "Address.Zip", Kind: ChangeModified, Old: "75001", New: "75002"},
+		{Path: "Home.City", Kind: ChangeModified, Old: "Lyon", New: "Nice"},
+		{Path: "Tags[1]", Kind: ChangeModified, Old: "b", New: "c"},
+		{Path: "Tags[2]", Kind: ChangeAdded, New: "d"},
+		{Path: "Items[0].Qty", Kind: ChangeModified, Old: 1, New: 2},
+		{Path: "Labels[region]", Kind: ChangeAdded, New: "eu"},
+		{Path: "Labels[tier]", Kind: ChangeRemoved, Old: "gold"},
+		{Path: "Limits[2]", Kind: ChangeModified, Old: 1, New: 5},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+}
+
+func TestDiff_Equal(t *testing.T) {
+	email, otherEmail := "alice@example.com", "alice@example.com"
+	a := diffUser{
+		Name:    "Alice",
+		Email:   &email,
+		Address: &diffAddress{City: "Paris"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"team": "core"},
+	}
+	b := diffUser{
+		Name:    "Alice",
+		Email:   &otherEmail,
+		Address: &diffAddress{City: "Paris"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"team": "core"},
+	}
+
+	changes, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiff_CrossType(t *testing.T) {
+	type ItemDTO struct {
+		SKU string
+		Qty int64
+	}
+	type UserDTO struct {
+		FullName string `map:"Name"`
+		Age      string `mapconv:"int"`
+		Address  diffAddress
+		Items    []ItemDTO
+		Extra    string
+	}
+
+	a := diffUser{
+		Name:    "Alice",
+		Age:     30,
+		Address: &diffAddress{City: "Paris", Zip: "75001"},
+		Items:   []diffItem{{SKU: "x", Qty: 1}},
+	}
+	b := UserDTO{
+		FullName: "Alice",
+		Age:      "31",
+		Address:  diffAddress{City: "Paris", Zip: "75001"},
+		Items:    []ItemDTO{{SKU: "x", Qty: 1}, {SKU: "y", Qty: 3}},
+	}
+
+	changes, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Change{
+		{Path: "Age", Kind: ChangeModified, Old: 30, New: 31},
+		{Path: "Items[1]", Kind: ChangeAdded, New: diffItem{SKU: "y", Qty: 3}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+}
+
+func TestDiff_PointerAdded(t *testing.T) {
+	a := diffUser{Name: "Alice"}
+	b := diffUser{Name: "Alice", Address: &diffAddress{City: "Paris", Zip: "75001"}}
+
+	changes, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Kind != ChangeAdded || changes[0].Path != "Address" {
+		t.Fatalf("expected Address to be added, got %+v", changes)
+	}
+	if got := changes[0].New.(*diffAddress); *got != *b.Address {
+		t.Errorf("expected new address %+v, got %+v", b.Address, got)
+	}
+}
+
+func TestDiff_Options(t *testing.T) {
+	type UserPatch struct {
+		Name     string `json:"name"`
+		Age      int    `json:"age"`
+		Nickname Optional[string]
+		Email    Optional[*string]
+	}
+	type Target struct {
+		Name     string `json:"name"`
+		Age      int    `json:"age"`
+		Nickname string
+		Email    *string
+	}
+
+	email := "a@example.com"
+	a := Target{Name: "Alice", Age: 30, Nickname: "Al", Email: &email}
+
+	changes, err := Diff(a, UserPatch{Name: "Alicia", Email: Null[*string]()}, WithTagName("json"), WithIgnoreZeroSource())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Change{
+		{Path: "Name", Kind: ChangeModified, Old: "Alice", New: "Alicia"},
+		{Path: "Email", Kind: ChangeRemoved, Old: &email},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+
+	m := New(WithIgnoreZeroSource())
+	changes, err = m.Diff(a, UserPatch{Nickname: Some("Ali")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != "Nickname" || changes[0].New != "Ali" {
+		t.Errorf("expected only Nickname to change, got %+v", changes)
+	}
+}
+
+func TestDiff_Errors(t *testing.T) {
+	type BadDTO struct {
+		Age  string `mapconv:"int"`
+		Name []int
+	}
+
+	a := diffUser{Name: "Alice", Age: 30}
+	_, err := Diff(a, BadDTO{Age: "x"}, WithIgnoreZeroSource())
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.FieldPath != "Age" || mappingErr.Code != CodeConversionFailed {
+		t.Errorf("expected conversion error at Age, got %v", err)
+	}
+
+	changes, err := Diff(a, BadDTO{Age: "x"}, WithCollectErrors())
+	var errs MappingErrors
+	if !errors.As(err, &errs) || !reflect.DeepEqual(collectedPaths(errs), []string{"Name", "Age"}) {
+		t.Errorf("expected collected errors at Name and Age, got %v", err)
+	}
+	if changes != nil {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	type Node struct{ Next *Node }
+	loopA := &Node{}
+	loopA.Next = loopA
+	loopB := &Node{}
+	loopB.Next = loopB
+	if _, err := Diff(loopA, loopB, WithMaxDepth(8)); !errors.Is(err, ErrDepthExceeded) {
+		t.Errorf("expected depth error for cyclic values, got %v", err)
+	}
+
+	if _, err := Diff(nil, a); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected nil input, got %v", err)
+	}
+	if _, err := Diff(a, (*diffUser)(nil)); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected nil input for a nil pointer, got %v", err)
+	}
+	if _, err := Diff(a, 5); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+}
+
+func TestChangeKind_String(t *testing.T) {
+	for kind, want := range map[ChangeKind]string{ChangeModified: "modified", ChangeAdded: "added", ChangeRemoved: "removed"} {
+		if got := kind.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
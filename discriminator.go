@@ -0,0 +1,163 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// discriminator selects the concrete type built for an interface destination
+// from the value of a source field.
+type discriminator struct {
+	field   string
+	keyType reflect.Type
+	types   map[any]reflect.Type
+}
+
+// RegisterDiscriminator registers a rule for mapping tagged unions into
+// destinations of the interface type I. The value of the source field named
+// field selects the concrete type to build from types, whose values are
+// prototypes of the destination types. Registered rules are used by every
+// mapping call made through [Map] and [MapWithOptions]; [Mapper] instances
+// created with [New] take them through [WithDiscriminator] instead.
+//
+// Example:
+//
+//	mapper.RegisterDiscriminator("Type", map[string]Payment{
+//	    "card": CardPayment{},
+//	    "bank": &BankPayment{},
+//	})
+//
+//	// PaymentDTO{Type: "bank", IBAN: "..."} maps into a *BankPayment
+//	err := mapper.Map(&order, orderDTO)
+//
+// Wherever a struct, or a pointer to one, is mapped into an I, whether a
+// field, a slice element, a map value or the value held by an interface
+// source, a new value of the selected type is mapped from it with the usual
+// rules and stored. A pointer prototype builds a pointer. The source field is
+// matched by name or tag like other fields, and its value is converted to K,
+// so a string enum or an int32 field can select from a map keyed by string or
+// int. A value missing from types fails with a [*MappingError] with code
+// [CodeUnknownDiscriminator].
+//
+// Sources without the field are mapped like other interface destinations, and
+// an implementation registered for the source type with
+// [RegisterImplementation] takes precedence. Registering a rule for an
+// interface type that already has one replaces it. RegisterDiscriminator is
+// safe for concurrent use, but is typically called during program
+// initialization.
+func RegisterDiscriminator[I any, K comparable](field string, types map[K]I) {
+	t, d := newDiscriminator(field, types)
+	registeredDiscriminators.store(t, d)
+}
+
+// WithDiscriminator adds a discriminator rule for a single mapping call, or
+// for every call of a [Mapper] when passed to [New]. It takes precedence over
+// a rule registered for the same interface type with [RegisterDiscriminator].
+func WithDiscriminator[I any, K comparable](field string, types map[K]I) Option {
+	t, d := newDiscriminator(field, types)
+	return func(c *config) {
+		c.discriminators = copyMap(c.discriminators, 1)
+		c.discriminators[t] = d
+	}
+}
+
+func newDiscriminator[I any, K comparable](field string, types map[K]I) (reflect.Type, *discriminator) {
+	d := &discriminator{
+		field:   field,
+		keyType: typeFor[K](),
+		types:   make(map[any]reflect.Type, len(types)),
+	}
+	for k, v := range types {
+		if t := reflect.TypeOf(v); t != nil {
+			d.types[k] = t
+		}
+	}
+	return typeFor[I](), d
+}
+
+// hasDiscriminators reports whether any discriminator rule may apply to this call.
+func (c *config) hasDiscriminators() bool {
+	return hasEntries(c.discriminators, &registeredDiscriminators, c.registered)
+}
+
+// lookupDiscriminator returns the rule for the interface type iface,
+// preferring per-call rules over registered ones.
+func (c *config) lookupDiscriminator(iface reflect.Type) (*discriminator, bool) {
+	return lookupEntry(c.discriminators, &registeredDiscriminators, c.registered, iface)
+}
+
+// assignDiscriminated maps src into the concrete type selected by the
+// discriminator field of src and stores it in the interface destination dst.
+// It reports false when src is not a struct with the field.
+func assignDiscriminated(dst, src reflect.Value, d *discriminator, srcStructType, dstStructType reflect.Type, basePath, fieldName, convertTo string, cfg *config, depth int) (bool, error) {
+	s := src
+	for s.Kind() == reflect.Ptr {
+		if s.IsNil() {
+			return false, nil
+		}
+		s = s.Elem()
+	}
+	if s.Kind() != reflect.Struct {
+		return false, nil
+	}
+
+	meta, err := cfg.mapper.getStructMeta(s.Type(), cfg.tagName)
+	if err != nil {
+		return false, nil
+	}
+	fm, ok := meta.FieldsByName[d.field]
+	if !ok {
+		fm, ok = meta.FieldsByTag[d.field]
+	}
+	if !ok {
+		return false, nil
+	}
+
+	value := s.Field(fm.Index[0])
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	impl, ok := d.implementationFor(value)
+	if !ok {
+		return true, &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: buildPath(basePath, fieldName),
+			Reason:    "unknown discriminator " + d.field + "=" + formatDiscriminator(value) + " for " + dst.Type().String(),
+			Code:      CodeUnknownDiscriminator,
+		}
+	}
+
+	return true, assignImplementation(dst, src, impl, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth)
+}
+
+// implementationFor returns the type registered for the discriminator value v.
+func (d *discriminator) implementationFor(v reflect.Value) (reflect.Type, bool) {
+	if v.Kind() == reflect.Interface {
+		// A nil interface selects nothing
+		return nil, false
+	}
+	// Named types and other integer sizes are converted, but numbers are
+	// never converted to strings, which would yield a rune
+	if v.Type() != d.keyType && v.Type().ConvertibleTo(d.keyType) && (v.Kind() == reflect.String) == (d.keyType.Kind() == reflect.String) {
+		v = v.Convert(d.keyType)
+	}
+	if !v.Type().AssignableTo(d.keyType) || !v.Comparable() {
+		return nil, false
+	}
+	t, ok := d.types[v.Interface()]
+	return t, ok
+}
+
+// formatDiscriminator formats a discriminator value for error messages.
+func formatDiscriminator(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Interface:
+		return "nil"
+	case reflect.String:
+		return strconv.Quote(v.String())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,196 @@
+package mapper
+
+import (
+	"errors"
+	"testing"
+)
+
+type discPayment interface {
+	Method() string
+}
+
+type discKind string
+
+type discPaymentDTO struct {
+	Kind   discKind `map:"Type"`
+	Amount string   `mapconv:"int"`
+	Number string
+	IBAN   string
+}
+
+type discCard struct {
+	Amount int
+	Number string
+}
+
+func (discCard) Method() string { return "card" }
+
+type discBank struct {
+	Amount int
+	IBAN   string
+}
+
+func (*discBank) Method() string { return "bank" }
+
+type discOrderDTO struct {
+	Payment  discPaymentDTO
+	Refund   *discPaymentDTO
+	Payments []discPaymentDTO
+	ByID     map[string]any
+}
+
+type discOrder struct {
+	Payment  discPayment
+	Refund   discPayment
+	Payments []discPayment
+	ByID     map[string]discPayment
+}
+
+var discTypes = map[string]discPayment{
+	"card": discCard{},
+	"bank": &discBank{},
+}
+
+func TestDiscriminator_SelectsType(t *testing.T) {
+	src := discOrderDTO{
+		Payment:  discPaymentDTO{Kind: "card", Amount: "10", Number: "4242"},
+		Refund:   &discPaymentDTO{Kind: "bank", Amount: "3", IBAN: "DE89"},
+		Payments: []discPaymentDTO{{Kind: "bank", Amount: "1"}, {Kind: "card", Amount: "2"}},
+		ByID:     map[string]any{"p1": discPaymentDTO{Kind: "card", Amount: "5"}},
+	}
+	var dst discOrder
+	if err := MapWithOptions(&dst, src, WithDiscriminator("Type", discTypes)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if card, ok := dst.Payment.(discCard); !ok || card.Amount != 10 || card.Number != "4242" {
+		t.Errorf("expected a mapped discCard, got %#v", dst.Payment)
+	}
+	if bank, ok := dst.Refund.(*discBank); !ok || bank.Amount != 3 || bank.IBAN != "DE89" {
+		t.Errorf("expected a pointer prototype to build a *discBank, got %#v", dst.Refund)
+	}
+	if dst.Payments[0].Method() != "bank" || dst.Payments[1].Method() != "card" {
+		t.Errorf("expected slice elements to be discriminated, got %#v", dst.Payments)
+	}
+	if card, ok := dst.ByID["p1"].(discCard); !ok || card.Amount != 5 {
+		t.Errorf("expected map values held in interfaces to be discriminated, got %#v", dst.ByID["p1"])
+	}
+}
+
+func TestDiscriminator_UnknownValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    discOrderDTO
+		path   string
+		reason string
+	}{
+		{
+			"field",
+			discOrderDTO{
+				Payment: discPaymentDTO{Kind: "crypto", Amount: "10"},
+				Refund:  &discPaymentDTO{Kind: "bank", Amount: "3"},
+			},
+			"Payment",
+			`unknown discriminator Type="crypto" for mapper.discPayment`,
+		},
+		{
+			"slice element",
+			discOrderDTO{
+				Payment:  discPaymentDTO{Kind: "card", Amount: "10"},
+				Refund:   &discPaymentDTO{Kind: "bank", Amount: "3"},
+				Payments: []discPaymentDTO{{Kind: "bank", Amount: "1"}, {Amount: "2"}},
+			},
+			"Payments[1]",
+			`unknown discriminator Type="" for mapper.discPayment`,
+		},
+		{
+			"map value",
+			discOrderDTO{
+				Payment: discPaymentDTO{Kind: "card", Amount: "10"},
+				Refund:  &discPaymentDTO{Kind: "bank", Amount: "3"},
+				ByID:    map[string]any{"p1": discPaymentDTO{Kind: "cash"}},
+			},
+			"ByID[p1]",
+			`unknown discriminator Type="cash" for mapper.discPayment`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst discOrder
+			err := MapWithOptions(&dst, tt.src, WithDiscriminator("Type", discTypes))
+
+			var mappingErr *MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("expected *MappingError, got %v", err)
+			}
+			if mappingErr.FieldPath != tt.path || !errors.Is(err, ErrUnknownDiscriminator) {
+				t.Errorf("expected unknown discriminator at %q, got %v", tt.path, err)
+			}
+			if mappingErr.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, mappingErr.Reason)
+			}
+		})
+	}
+}
+
+func TestDiscriminator_KeyTypes(t *testing.T) {
+	type Src struct {
+		Code    int32
+		Payment any
+	}
+	type Dst struct{ Payment discPayment }
+
+	var dst Dst
+	err := MapWithOptions(&dst, Src{Payment: struct{ Code int32 }{Code: 2}},
+		WithDiscriminator("Code", map[int]discPayment{1: discCard{}, 2: &discBank{}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := dst.Payment.(*discBank); !ok {
+		t.Errorf("expected the int32 field to be converted to the key type, got %#v", dst.Payment)
+	}
+
+	// Sources without the field fall back to copying values that implement the interface
+	err = MapWithOptions(&dst, Src{Payment: discCard{Number: "1"}},
+		WithDiscriminator("Kind", map[string]discPayment{"card": discCard{}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card, ok := dst.Payment.(discCard); !ok || card.Number != "1" {
+		t.Errorf("expected the value to be copied, got %#v", dst.Payment)
+	}
+}
+
+func TestDiscriminator_Registered(t *testing.T) {
+	RegisterDiscriminator("Type", discTypes)
+	t.Cleanup(func() {
+		registeredDiscriminators.delete(typeFor[discPayment]())
+	})
+
+	src := discOrderDTO{
+		Payment: discPaymentDTO{Kind: "card", Amount: "10"},
+		Refund:  &discPaymentDTO{Kind: "bank", Amount: "3"},
+	}
+	var dst discOrder
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := dst.Payment.(discCard); !ok {
+		t.Errorf("expected the registered rule, got %#v", dst.Payment)
+	}
+
+	// An implementation for the source type takes precedence
+	err := MapWithOptions(&dst, src, WithImplementation[discPayment, discPaymentDTO, discBank]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := dst.Payment.(*discBank); !ok {
+		t.Errorf("expected the implementation to take precedence, got %#v", dst.Payment)
+	}
+
+	// Instances take rules through WithDiscriminator only
+	if err := New().Map(&dst, src); !errors.Is(err, ErrIncompatibleTypes) {
+		t.Errorf("expected instances to ignore registered rules, got %v", err)
+	}
+}
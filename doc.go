@@ -162,6 +162,141 @@
 //	// *dst.Name == "Rafa"
 //
 // Nil pointers are handled gracefully and do not overwrite destination values.
+// Use [WithReusePointers] or the `mapptr:"reuse"` tag to map into an existing
+// pointee instead of allocating a new one. [WithPreserveReferences] maps a
+// source pointer reached more than once to a single copy, so shared objects
+// stay shared and cyclic graphs can be mapped.
+//
+// # Optional Fields
+//
+// [Optional] distinguishes an absent field from one explicitly set to zero or
+// null. Absent optionals are skipped, null ones clear the destination and set
+// ones are mapped like plain fields:
+//
+//	patch := PatchUser{Name: mapper.Some(""), Email: mapper.Null[*string]()}
+//	err := mapper.Map(&user, patch)
+//
+// Any struct type with a Get() (T, bool) method, and optionally an
+// IsSet() bool method, is recognized the same way.
+//
+// # JSON Merge Patch
+//
+// [ApplyMergePatch] applies an RFC 7396 JSON Merge Patch document onto a
+// struct: absent members are left unchanged, null clears a field and nested
+// objects are merged recursively:
+//
+//	err := mapper.ApplyMergePatch(&user, body, mapper.WithTagName("json"))
+//
+// # Structural Diff
+//
+// [Diff] lists the differences between two structs as [Change] values with
+// a path, a [ChangeKind] and the old and new values. Fields are matched and
+// converted as in [Map], so a DTO can be compared with its entity:
+//
+//	changes, err := mapper.Diff(user, req)
+//
+// [CreatePatch] expresses the same differences as an RFC 6902 JSON [Patch]
+// with JSON Pointer paths named by the configured tag, and [Patch.Inverse]
+// returns the patch that undoes it:
+//
+//	patch, err := mapper.CreatePatch(before, after, mapper.WithTagName("json"))
+//	undo := patch.Inverse()
+//
+// # Typed Helpers
+//
+// [To], [MapSlice] and [MapMap] return mapped values directly:
+//
+//	user, err := mapper.To[User](dto)
+//	users, err := mapper.MapSlice[UserDTO](entities)
+//	byID, err := mapper.MapMap[string, UserDTO](entitiesByID)
+//
+// [Clone] returns a deep copy of a value of any type:
+//
+//	snapshot, err := mapper.Clone(order)
+//
+// # Custom Converters
+//
+// Use [RegisterConverter] to convert between types the built-in rules do not
+// handle. A converter is used wherever a value of exactly its source type is
+// assigned to exactly its destination type, including slice elements, map
+// values and pointer elements:
+//
+//	mapper.RegisterConverter(func(id uuid.UUID) (string, error) {
+//	    return id.String(), nil
+//	})
+//
+// [WithConverter] supplies a converter for a single call. Explicit mapconv
+// tags take precedence over converters.
+//
+// # Context and Cancellation
+//
+// [MapContext] maps under the control of a context. The context is checked
+// periodically while slices and maps are mapped, and cancellation is returned
+// as a [*MappingError] matching [ErrCanceled] and ctx.Err():
+//
+//	err := mapper.MapContext(ctx, &report, bigExport)
+//
+// Converters added with [WithContextConverter] or [RegisterContextConverter]
+// receive the context of the call, as do the context-aware variants of the
+// mapping hooks and self-mapping methods, such as [BeforeMapperContext] and
+// [MapFromerContext].
+//
+// # Factories
+//
+// [RegisterFactory] and [WithFactory] supply constructors for types that must
+// not start as zero values. The engine calls them whenever it allocates a
+// fresh value of the type, such as a slice element or pointer target:
+//
+//	mapper.RegisterFactory(func() Order {
+//	    return Order{ID: uuid.New(), Meta: map[string]string{}}
+//	})
+//
+// # Mapping Hooks
+//
+// Destination types implementing [BeforeMapper] or [AfterMapper] are called
+// before and after their fields are mapped, at every nesting level, so
+// derived fields need no second pass:
+//
+//	func (o *Order) AfterMap(src any) error {
+//	    o.Total = o.Subtotal + o.Tax
+//	    return nil
+//	}
+//
+// Source types can implement [SourceBeforeMapper] and [SourceAfterMapper].
+// Hook errors are returned as a [*MappingError] matching [ErrHookFailed].
+//
+// # Self-Mapping Types
+//
+// A destination type implementing [MapFromer], or a source type implementing
+// [MapToer], maps itself wherever it appears in the tree. The methods report
+// whether they handled the value; unhandled values fall back to the built-in
+// rules:
+//
+//	func (e *Email) MapFrom(src any) (bool, error) {
+//	    s, ok := src.(string)
+//	    if !ok {
+//	        return false, nil
+//	    }
+//	    return true, e.Parse(s)
+//	}
+//
+// # Interface Fields
+//
+// Interface-typed source fields, such as any, are mapped from the value they
+// hold, and a nil interface zeroes the destination field. Interface-typed destination fields receive a deep copy of the source
+// value, or a new value of the concrete type registered for the source type
+// with [RegisterImplementation] or [WithImplementation]:
+//
+//	mapper.RegisterImplementation[Payload, ClickDTO, Click]()
+//
+// [RegisterDiscriminator] and [WithDiscriminator] map tagged unions: a source
+// field selects the concrete destination type, and unknown values fail with
+// [ErrUnknownDiscriminator]:
+//
+//	mapper.RegisterDiscriminator("Type", map[string]Payment{
+//	    "card": CardPayment{},
+//	    "bank": &BankPayment{},
+//	})
 //
 // # Options
 //
@@ -176,9 +311,19 @@
 //	// Error on missing source fields
 //	err := mapper.MapWithOptions(&dst, src, mapper.WithStrictMode())
 //
+//	// Error on source fields that are not mapped anywhere
+//	err := mapper.MapWithOptions(&dst, src, mapper.WithStrictSource())
+//
 //	// Increase max depth for deeply nested structs
 //	err := mapper.MapWithOptions(&dst, src, mapper.WithMaxDepth(100))
 //
+//	// Report every failing field instead of stopping at the first
+//	err := mapper.MapWithOptions(&dst, src, mapper.WithCollectErrors())
+//
+//	// Record the fields that were written or skipped, with old and new values
+//	var report mapper.Report
+//	err := mapper.MapWithOptions(&dst, src, mapper.WithChangeReport(&report))
+//
 //	// Combine multiple options
 //	err := mapper.MapWithOptions(&dst, src,
 //	    mapper.WithTagName("json"),
@@ -186,6 +331,21 @@
 //	    mapper.WithStrictMode(),
 //	)
 //
+// [DryRun] returns the same report without modifying the destination.
+//
+// # Mapper Instances
+//
+// [New] creates a [Mapper] with its own default options and caches, isolated
+// from other instances and from the package-level functions:
+//
+//	apiMapper := mapper.New(mapper.WithTagName("json"), mapper.WithStrictMode())
+//	err := apiMapper.Map(&resp, user)
+//
+// The Register functions fill process-global registries used by the
+// package-level functions only; instances take converters, factories,
+// implementations and discriminators as options. Generated mapping functions
+// are process-global and shared by every instance.
+//
 // # Patch Semantics
 //
 // Use [WithIgnoreZeroSource] for partial updates where only non-zero values
@@ -197,6 +357,20 @@
 //	err := mapper.MapWithOptions(&existing, patch, mapper.WithIgnoreZeroSource())
 //	// existing = {Name: "Alicia", Email: "rafa@old.com", Age: 25}
 //
+// # Merge Strategies
+//
+// Slice and map fields replace the destination collection by default. Use
+// [WithMergeStrategy] or the mapmerge and mapkey tags to combine them with the
+// existing values instead:
+//
+//	type Team struct {
+//	    Members []Member          `mapkey:"ID"`            // update by ID, add new
+//	    Tags    []string          `mapmerge:"union"`       // add missing tags
+//	    Labels  map[string]string `mapmerge:"merge,prune"` // mirror the source keys
+//	}
+//
+// See [MergeStrategy] for the available strategies.
+//
 // # Error Handling
 //
 // Errors are returned as [*MappingError] with detailed context:
@@ -209,25 +383,37 @@
 //	    }
 //	}
 //
+// Each error has a [ErrorCode] and matches a sentinel such as
+// [ErrConversionFailed] with errors.Is. The underlying error, such as the
+// *strconv.NumError of a failed mapconv conversion, is reachable through
+// errors.As.
+//
+// With [WithCollectErrors], every failure is reported in a [MappingErrors].
+//
 // # Performance
 //
-// The mapper uses struct metadata caching to minimize reflection overhead.
-// First-time mapping of a struct type incurs reflection cost, but subsequent
-// mappings use cached metadata for faster execution.
+// The mapper compiles a mapping plan for each source/destination type pair.
+// A plan holds the resolved field pairs, the assignment strategy chosen for
+// each pair, and the bound mapconv parsers. First-time mapping of a type pair
+// incurs reflection cost, but subsequent mappings execute the cached plan.
+//
+// For performance-critical code paths where nanoseconds matter, the mapper-gen
+// command (github.com/tariklabs/mapper/cmd/mapper-gen) generates reflection-free
+// mapping functions that follow the same rules. Generated files register their
+// functions with [RegisterGenerated], and [Map], [MapWithOptions] and the
+// [Mapper] methods dispatch to them when the type pair and options match.
 //
-// For performance-critical code paths where nanoseconds matter, consider
-// manual field assignment. For typical application code (API handlers, DTOs),
-// the mapper provides a good balance of convenience and performance.
+// For typical application code (API handlers, DTOs), the reflection-based
+// mapper provides a good balance of convenience and performance.
 //
 // # Thread Safety
 //
-// All functions are safe for concurrent use. The internal metadata cache uses
-// sync.Map for thread-safe access.
+// All functions and [Mapper] methods are safe for concurrent use. Each Mapper
+// keeps its metadata and plan caches in a sync.Map; the package-level functions
+// share a default instance.
 //
 // # Limitations
 //
 //   - Only exported (public) fields are mapped
-//   - Interface types are not supported as field types
-//   - No custom converter functions (only built-in type conversions)
-//   - Circular references are protected by depth limit, not runtime detection
+//   - Circular references fail with a depth error unless [WithPreserveReferences] is set
 package mapper
@@ -5,22 +5,32 @@ import (
 )
 
 func runMapping(dst any, src any, cfg *config) error {
+	if cfg.report != nil {
+		cfg.report.Fields = cfg.report.Fields[:0]
+	}
+
 	if dst == nil || src == nil {
 		return &MappingError{
 			SrcType:   typeOf(src),
 			DstType:   typeOf(dst),
 			FieldPath: "",
 			Reason:    "nil src or dst",
+			Code:      CodeNilInput,
 		}
 	}
 
 	dstVal := reflect.ValueOf(dst)
 	if dstVal.Kind() != reflect.Ptr || dstVal.IsNil() {
+		code := CodeInvalidInput
+		if dstVal.Kind() == reflect.Ptr {
+			code = CodeNilInput
+		}
 		return &MappingError{
 			SrcType:   typeOf(src),
 			DstType:   typeOf(dst),
 			FieldPath: "",
 			Reason:    "dst must be a non-nil pointer to struct",
+			Code:      code,
 		}
 	}
 
@@ -31,6 +41,7 @@ func runMapping(dst any, src any, cfg *config) error {
 			DstType:   typeOf(dst),
 			FieldPath: "",
 			Reason:    "dst must point to a struct",
+			Code:      CodeInvalidInput,
 		}
 	}
 
@@ -42,6 +53,7 @@ func runMapping(dst any, src any, cfg *config) error {
 				DstType:   typeOf(dst),
 				FieldPath: "",
 				Reason:    "src is a nil pointer",
+				Code:      CodeNilInput,
 			}
 		}
 		srcVal = srcVal.Elem()
@@ -52,184 +64,103 @@ func runMapping(dst any, src any, cfg *config) error {
 			DstType:   typeOf(dst),
 			FieldPath: "",
 			Reason:    "src must be a struct or pointer to struct",
+			Code:      CodeInvalidInput,
 		}
 	}
 
 	srcType := srcVal.Type()
 	dstType := dstElem.Type()
 
-	srcMeta, err := getStructMeta(srcType, cfg.tagName)
-	if err != nil {
+	if err := cfg.checkContext(0, srcType, dstType, ""); err != nil {
 		return err
 	}
-	dstMeta, err := getStructMeta(dstType, cfg.tagName)
+
+	if gen, ok := lookupGenerated(srcType, dstType, cfg); ok {
+		return gen.call(dst, src)
+	}
+
+	// A root passed by pointer can be referenced by its own fields
+	if rootPtr := reflect.ValueOf(src); cfg.preserveRefs && rootPtr.Kind() == reflect.Ptr {
+		cfg.rememberRef(rootPtr, dstVal)
+	}
+
+	plan, err := cfg.mapper.getStructPlan(srcType, dstType, cfg.tagName)
 	if err != nil {
 		return err
 	}
 
-	// Iterate over Fields slice for better cache locality than map iteration
-	for _, dstFieldMeta := range dstMeta.Fields {
-		dstName := dstFieldMeta.Name
-		srcFieldMeta, ok := srcMeta.FieldsByName[dstName]
-
-		if !ok {
-			srcFieldMeta, ok = srcMeta.FieldsByTag[dstName]
+	if plan.hooks != 0 {
+		if err := runBeforeHooks(cfg.context(), plan, dstElem, srcVal, srcType, dstType, ""); err != nil {
+			return err
 		}
+	}
 
-		if !ok {
-			if cfg.strictMode {
-				return &MappingError{
+	// Iterate over the compiled field plans; matching and strategy selection
+	// were resolved once when the plan was built.
+	var errs MappingErrors
+	for i := range plan.fields {
+		fp := &plan.fields[i]
+
+		if fp.unmatched {
+			if cfg.isStrict(dstType) {
+				err := &MappingError{
 					SrcType:   srcType.String(),
 					DstType:   dstType.String(),
-					FieldPath: dstName,
+					FieldPath: fp.name,
 					Reason:    "no matching source field found",
+					Code:      CodeUnmatchedField,
+				}
+				if err := cfg.recordError(&errs, err); err != nil {
+					return err
 				}
 			}
+			if cfg.report != nil {
+				reportSkipped(FieldUnmatched, fp, dstElem.Field(fp.dstIndex), "", cfg)
+			}
 			continue
 		}
 
-		srcField := srcVal.FieldByIndex(srcFieldMeta.Index)
-		dstField := dstElem.FieldByIndex(dstFieldMeta.Index)
+		srcField := srcVal.Field(fp.srcIndex)
+		dstField := dstElem.Field(fp.dstIndex)
 
 		if cfg.ignoreZeroSource && srcField.IsZero() {
+			if cfg.report != nil {
+				reportSkipped(FieldSkippedZero, fp, dstField, "", cfg)
+			}
 			continue
 		}
 
-		if err := assignValue(dstField, srcField, srcType, dstType, dstName, srcFieldMeta.ConvertTo, cfg.tagName, cfg.maxDepth); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// assignValue tries to assign src to dst, handling basic cases and pointer/value combinations.
-func assignValue(dst, src reflect.Value, srcType, dstType reflect.Type, fieldPath string, convertTo string, tagName string, depth int) error {
-	if depth <= 0 {
-		return &MappingError{
-			SrcType:   srcType.String(),
-			DstType:   dstType.String(),
-			FieldPath: fieldPath,
-			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+		if cfg.report != nil {
+			if err := reportAssign(fp, dstField, srcField, srcType, dstType, "", cfg, cfg.maxDepth); err != nil {
+				if err = cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+			}
+			continue
 		}
-	}
 
-	if !dst.CanSet() {
-		return &MappingError{
-			SrcType:   srcType.String(),
-			DstType:   dstType.String(),
-			FieldPath: fieldPath,
-			Reason:    "destination field cannot be set",
+		if err := assignField(fp, dstField, srcField, srcType, dstType, "", cfg, cfg.maxDepth); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
 
-	sType := src.Type()
-	dType := dst.Type()
-
-	if convertTo != "" && sType.Kind() == reflect.String {
-		converted, err := convertString(src.String(), convertTo, srcType, dstType, fieldPath)
-		if err != nil {
+	if cfg.strictSource {
+		if err := reportUnconsumed(plan, srcType, dstType, "", cfg, &errs); err != nil {
 			return err
 		}
-		dst.Set(converted.Convert(dType))
-		return nil
 	}
 
-	srcKind := sType.Kind()
-	dstKind := dType.Kind()
-
-	// Fast path: primitive types (not struct, slice, map, ptr)
-	// Check these first as they're the most common case
-	if srcKind != reflect.Struct && srcKind != reflect.Slice && srcKind != reflect.Map && srcKind != reflect.Ptr &&
-		dstKind != reflect.Struct && dstKind != reflect.Slice && dstKind != reflect.Map && dstKind != reflect.Ptr {
-		if sType.AssignableTo(dType) {
-			dst.Set(src)
-			return nil
-		}
-		if sType.ConvertibleTo(dType) {
-			dst.Set(src.Convert(dType))
-			return nil
-		}
-		return &MappingError{
-			SrcType:   srcType.String(),
-			DstType:   dstType.String(),
-			FieldPath: fieldPath,
-			Reason:    "incompatible field types: " + sType.String() + " -> " + dType.String(),
-		}
-	}
-
-	// Slow path: complex types requiring recursion
-	if srcKind == reflect.Struct && dstKind == reflect.Struct {
-		return assignStruct(dst, src, srcType, dstType, fieldPath, tagName, depth-1)
-	}
-
-	if srcKind == reflect.Slice && dstKind == reflect.Slice {
-		return assignSlice(dst, src, srcType, dstType, fieldPath, tagName, depth-1)
-	}
-
-	if srcKind == reflect.Map && dstKind == reflect.Map {
-		return assignMap(dst, src, srcType, dstType, fieldPath, tagName, depth-1)
-	}
-
-	if srcKind == reflect.Ptr && dstKind == reflect.Ptr {
-		if src.IsNil() {
-			dst.Set(reflect.Zero(dType))
-			return nil
-		}
-
-		srcElemType := sType.Elem()
-		dstElemType := dType.Elem()
-
-		if srcElemType == dstElemType {
-			elemKind := srcElemType.Kind()
-			if elemKind != reflect.Struct && elemKind != reflect.Slice && elemKind != reflect.Map && elemKind != reflect.Ptr {
-				newPtr := reflect.New(dstElemType)
-				newPtr.Elem().Set(src.Elem())
-				dst.Set(newPtr)
-				return nil
+	if plan.hooks != 0 {
+		if err := runAfterHooks(cfg.context(), plan, dstElem, srcVal, srcType, dstType, ""); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
 			}
 		}
-
-		newPtr := reflect.New(dstElemType)
-		if err := assignValue(newPtr.Elem(), src.Elem(), srcType, dstType, fieldPath, convertTo, tagName, depth-1); err != nil {
-			return err
-		}
-		dst.Set(newPtr)
-		return nil
-	}
-
-	if srcKind == reflect.Ptr && dstKind != reflect.Ptr {
-		if src.IsNil() {
-			return nil
-		}
-		return assignValue(dst, src.Elem(), srcType, dstType, fieldPath, convertTo, tagName, depth-1)
 	}
 
-	if srcKind != reflect.Ptr && dstKind == reflect.Ptr {
-		newVal := reflect.New(dType.Elem())
-		if err := assignValue(newVal.Elem(), src, srcType, dstType, fieldPath, convertTo, tagName, depth-1); err != nil {
-			return err
-		}
-		dst.Set(newVal)
-		return nil
-	}
-
-	if sType.AssignableTo(dType) {
-		dst.Set(src)
-		return nil
-	}
-
-	if sType.ConvertibleTo(dType) {
-		dst.Set(src.Convert(dType))
-		return nil
-	}
-
-	return &MappingError{
-		SrcType:   srcType.String(),
-		DstType:   dstType.String(),
-		FieldPath: fieldPath,
-		Reason:    "incompatible field types: " + sType.String() + " -> " + dType.String(),
-	}
+	return errs.orNil()
 }
 
 func typeOf(v any) string {
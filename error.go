@@ -1,6 +1,10 @@
 package mapper
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // MappingError describes a failure that occurred during struct mapping.
 // It provides detailed context about what went wrong and where.
@@ -24,6 +28,13 @@ import "fmt"
 //   - "cannot convert \"X\" to Y" - string conversion failed
 //   - "unsupported mapconv target type: X" - invalid mapconv tag value
 //   - "destination field cannot be set" - field is unexported
+//   - "source field is not mapped to any destination field" - WithStrictSource
+//     enabled, source field is not consumed
+//   - "AfterMap hook failed: ..." - a mapping hook returned an error
+//
+// Each error carries a [ErrorCode] in Code and matches the corresponding
+// sentinel error, such as [ErrConversionFailed], with [errors.Is]. The
+// underlying error, if any, is available through [errors.Unwrap].
 //
 // Example - Error handling:
 //
@@ -48,6 +59,14 @@ type MappingError struct {
 
 	// Reason describes why the mapping failed.
 	Reason string
+
+	// Code classifies the failure for programmatic handling.
+	// Each code has a matching sentinel error for use with [errors.Is].
+	Code ErrorCode
+
+	// Err is the underlying error, if any, such as the *strconv.NumError of
+	// a failed mapconv conversion or the error returned by a converter.
+	Err error
 }
 
 // Error implements the error interface and returns a formatted error message.
@@ -57,3 +76,168 @@ func (e *MappingError) Error() string {
 		e.SrcType, e.DstType, e.FieldPath, e.Reason,
 	)
 }
+
+// Unwrap returns the underlying error, if any.
+func (e *MappingError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error for the error's Code.
+//
+// Example:
+//
+//	if errors.Is(err, mapper.ErrConversionFailed) {
+//	    w.WriteHeader(http.StatusUnprocessableEntity)
+//	}
+func (e *MappingError) Is(target error) bool {
+	return e.Code != "" && codeSentinels[e.Code] == target
+}
+
+// ErrorCode is a machine-readable classification of a [MappingError].
+type ErrorCode string
+
+const (
+	// CodeNilInput means src or dst is nil or a nil pointer.
+	CodeNilInput ErrorCode = "nil_input"
+	// CodeInvalidInput means src or dst is not a struct or pointer to struct.
+	CodeInvalidInput ErrorCode = "invalid_input"
+	// CodeIncompatibleTypes means a source type cannot be assigned or
+	// converted to the destination type.
+	CodeIncompatibleTypes ErrorCode = "incompatible_types"
+	// CodeConversionFailed means a mapconv string could not be parsed.
+	CodeConversionFailed ErrorCode = "conversion_failed"
+	// CodeUnsupportedConversion means a mapconv tag names an unsupported type.
+	CodeUnsupportedConversion ErrorCode = "unsupported_conversion"
+	// CodeConverterFailed means a registered converter, or a MapFrom or
+	// MapTo method, returned an error.
+	CodeConverterFailed ErrorCode = "converter_failed"
+	// CodeDepthExceeded means the maximum nesting depth was exceeded.
+	CodeDepthExceeded ErrorCode = "depth_exceeded"
+	// CodeUnmatchedField means a destination field has no source in strict mode.
+	CodeUnmatchedField ErrorCode = "unmatched_field"
+	// CodeFieldNotSettable means a destination field cannot be set.
+	CodeFieldNotSettable ErrorCode = "field_not_settable"
+	// CodeUnmappedSource means a source field is not mapped to any
+	// destination field with WithStrictSource.
+	CodeUnmappedSource ErrorCode = "unmapped_source"
+	// CodeInvalidMerge means a merge strategy cannot be applied, for example
+	// because of an invalid mapmerge tag or a missing mapkey field.
+	CodeInvalidMerge ErrorCode = "invalid_merge"
+	// CodeCanceled means the context passed to MapContext was canceled or
+	// its deadline passed.
+	CodeCanceled ErrorCode = "canceled"
+	// CodeHookFailed means a BeforeMap, AfterMap, BeforeMapTo or AfterMapTo
+	// hook returned an error.
+	CodeHookFailed ErrorCode = "hook_failed"
+	// CodeUnknownDiscriminator means a discriminator field holds a value with
+	// no registered destination type.
+	CodeUnknownDiscriminator ErrorCode = "unknown_discriminator"
+)
+
+// Sentinel errors matching each [ErrorCode] with [errors.Is].
+var (
+	ErrNilInput              = errors.New("mapper: nil input")
+	ErrInvalidInput          = errors.New("mapper: invalid input")
+	ErrIncompatibleTypes     = errors.New("mapper: incompatible types")
+	ErrConversionFailed      = errors.New("mapper: conversion failed")
+	ErrUnsupportedConversion = errors.New("mapper: unsupported conversion")
+	ErrConverterFailed       = errors.New("mapper: converter failed")
+	ErrDepthExceeded         = errors.New("mapper: maximum nesting depth exceeded")
+	ErrUnmatchedField        = errors.New("mapper: no matching source field")
+	ErrFieldNotSettable      = errors.New("mapper: field cannot be set")
+	ErrUnmappedSource        = errors.New("mapper: source field not mapped")
+	ErrInvalidMerge          = errors.New("mapper: invalid merge configuration")
+	ErrCanceled              = errors.New("mapper: mapping canceled")
+	ErrHookFailed            = errors.New("mapper: hook failed")
+	ErrUnknownDiscriminator  = errors.New("mapper: unknown discriminator")
+)
+
+var codeSentinels = map[ErrorCode]error{
+	CodeNilInput:              ErrNilInput,
+	CodeInvalidInput:          ErrInvalidInput,
+	CodeIncompatibleTypes:     ErrIncompatibleTypes,
+	CodeConversionFailed:      ErrConversionFailed,
+	CodeUnsupportedConversion: ErrUnsupportedConversion,
+	CodeConverterFailed:       ErrConverterFailed,
+	CodeDepthExceeded:         ErrDepthExceeded,
+	CodeUnmatchedField:        ErrUnmatchedField,
+	CodeFieldNotSettable:      ErrFieldNotSettable,
+	CodeUnmappedSource:        ErrUnmappedSource,
+	CodeInvalidMerge:          ErrInvalidMerge,
+	CodeCanceled:              ErrCanceled,
+	CodeHookFailed:            ErrHookFailed,
+	CodeUnknownDiscriminator:  ErrUnknownDiscriminator,
+}
+
+// MappingErrors lists every failure of a mapping call made with
+// [WithCollectErrors], in the order the fields were visited.
+//
+// It supports [errors.As] and [errors.Is] by unwrapping to its elements:
+//
+//	err := mapper.MapWithOptions(&form, req, mapper.WithCollectErrors())
+//	var errs mapper.MappingErrors
+//	if errors.As(err, &errs) {
+//	    for _, e := range errs {
+//	        log.Printf("%s: %s", e.FieldPath, e.Reason)
+//	    }
+//	}
+type MappingErrors []*MappingError
+
+// Error implements the error interface and lists every collected error.
+func (e MappingErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "mapper: %d errors occurred:", len(e))
+	for _, me := range e {
+		b.WriteString("\n\t* ")
+		b.WriteString(me.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the collected errors for use with [errors.Is] and [errors.As].
+func (e MappingErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, me := range e {
+		errs[i] = me
+	}
+	return errs
+}
+
+// orNil returns e as an error, or nil when no errors were collected.
+func (e MappingErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// recordError handles an error returned while mapping a field, element or
+// map entry. Without [WithCollectErrors] it returns err so mapping stops.
+// In collect mode it appends err to errs and returns nil so mapping
+// continues, or returns errs once the error limit is reached.
+func (c *config) recordError(errs *MappingErrors, err error) error {
+	if !c.collectErrors {
+		return err
+	}
+
+	switch e := err.(type) {
+	case MappingErrors:
+		// Already counted when the nested call collected them
+		*errs = append(*errs, e...)
+	case *MappingError:
+		*errs = append(*errs, e)
+		c.errorCount++
+	default:
+		return err
+	}
+
+	// The error limit and cancellation stop the call
+	if (c.maxErrors > 0 && c.errorCount >= c.maxErrors) || c.canceled() {
+		return *errs
+	}
+	return nil
+}
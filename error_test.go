@@ -0,0 +1,334 @@
+package mapper
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type collectLine struct {
+	Qty string `mapconv:"int"`
+}
+
+type collectLineDst struct {
+	Qty int
+}
+
+type collectForm struct {
+	Name  string
+	Age   string `mapconv:"int"`
+	Score string `mapconv:"float64"`
+	Lines []collectLine
+	Attrs map[string]collectLine
+}
+
+type collectFormDst struct {
+	Name  string
+	Age   int
+	Score float64
+	Lines []collectLineDst
+	Attrs map[string]collectLineDst
+	Email string
+}
+
+func collectedPaths(errs MappingErrors) []string {
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.FieldPath
+	}
+	return paths
+}
+
+func TestCollectErrors(t *testing.T) {
+	src := collectForm{
+		Name:  "Alice",
+		Age:   "abc",
+		Score: "high",
+		Lines: []collectLine{{Qty: "1"}, {Qty: "x"}, {Qty: "y"}},
+		Attrs: map[string]collectLine{"a": {Qty: "2"}, "b": {Qty: "z"}},
+	}
+
+	var dst collectFormDst
+	err := MapWithOptions(&dst, src, WithCollectErrors(), WithStrictMode())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MappingErrors, got %T", err)
+	}
+
+	got := collectedPaths(errs)
+	sort.Strings(got)
+	want := []string{"Age", "Attrs[b].Qty", "Email", "Lines[1].Qty", "Lines[2].Qty", "Score"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected paths %v, got %v", want, got)
+	}
+
+	// Fields after a failure are still mapped
+	if dst.Name != "Alice" {
+		t.Errorf("expected Name = 'Alice', got %q", dst.Name)
+	}
+	if len(dst.Lines) != 3 || dst.Lines[0].Qty != 1 {
+		t.Errorf("expected Lines[0].Qty = 1, got %+v", dst.Lines)
+	}
+	if dst.Attrs["a"].Qty != 2 {
+		t.Errorf("expected Attrs[a].Qty = 2, got %+v", dst.Attrs)
+	}
+}
+
+func TestCollectErrors_ErrorsAs(t *testing.T) {
+	src := collectForm{
+		Name:  "Alice",
+		Age:   "abc",
+		Score: "high",
+		Lines: []collectLine{{Qty: "1"}, {Qty: "x"}, {Qty: "y"}},
+		Attrs: map[string]collectLine{"a": {Qty: "2"}, "b": {Qty: "z"}},
+	}
+
+	var dst collectFormDst
+	err := MapWithOptions(&dst, src, WithCollectErrors())
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		t.Fatalf("expected errors.As to find a *MappingError in %T", err)
+	}
+	if mappingErr.FieldPath != "Age" {
+		t.Errorf("expected first error at Age, got %q", mappingErr.FieldPath)
+	}
+
+	var errs MappingErrors
+	errors.As(err, &errs)
+	if !errors.Is(err, errs[1]) {
+		t.Error("expected errors.Is to match a collected error")
+	}
+
+	if !strings.Contains(err.Error(), "5 errors occurred") {
+		t.Errorf("expected error count in message, got %q", err.Error())
+	}
+}
+
+func TestCollectErrors_MaxErrors(t *testing.T) {
+	src := collectForm{
+		Age:   "abc",
+		Score: "high",
+		Lines: []collectLine{{Qty: "1"}, {Qty: "x"}, {Qty: "y"}},
+	}
+
+	var dst collectFormDst
+	err := MapWithOptions(&dst, src, WithMaxErrors(3))
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MappingErrors, got %T", err)
+	}
+	got := strings.Join(collectedPaths(errs), ",")
+	if got != "Age,Score,Lines[1].Qty" {
+		t.Errorf("expected to stop after 3 errors, got %v", got)
+	}
+}
+
+func TestCollectErrors_NoErrors(t *testing.T) {
+	src := collectForm{Name: "Alice", Age: "30", Score: "1.5"}
+
+	var dst collectFormDst
+	if err := MapWithOptions(&dst, src, WithCollectErrors()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dst.Age != 30 {
+		t.Errorf("expected Age 30, got %d", dst.Age)
+	}
+}
+
+func TestCollectErrors_SingleError(t *testing.T) {
+	src := collectForm{Age: "x", Score: "1"}
+
+	var dst collectFormDst
+	err := MapWithOptions(&dst, src, WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected one collected error, got %v", err)
+	}
+	if err.Error() != errs[0].Error() {
+		t.Errorf("expected single error message, got %q", err.Error())
+	}
+}
+
+func TestCollectErrors_FailFastByDefault(t *testing.T) {
+	src := collectForm{Age: "abc", Score: "1.5"}
+
+	var dst collectFormDst
+	err := Map(&dst, src)
+
+	if _, ok := err.(*MappingError); !ok {
+		t.Fatalf("expected *MappingError without collect mode, got %T", err)
+	}
+	if dst.Score != 0 {
+		t.Error("expected mapping to stop at the first error")
+	}
+}
+
+func TestMappingError_Codes(t *testing.T) {
+	type Inner struct{ A int }
+	type Node struct{ Next *Node }
+
+	tests := []struct {
+		name     string
+		run      func() error
+		code     ErrorCode
+		sentinel error
+	}{
+		{
+			name:     "nil input",
+			run:      func() error { return Map(nil, struct{}{}) },
+			code:     CodeNilInput,
+			sentinel: ErrNilInput,
+		},
+		{
+			name: "nil dst pointer",
+			run: func() error {
+				var dst *struct{}
+				return Map(dst, struct{}{})
+			},
+			code:     CodeNilInput,
+			sentinel: ErrNilInput,
+		},
+		{
+			name: "invalid input",
+			run: func() error {
+				var dst struct{}
+				return Map(&dst, 42)
+			},
+			code:     CodeInvalidInput,
+			sentinel: ErrInvalidInput,
+		},
+		{
+			name: "incompatible types",
+			run: func() error {
+				var dst struct{ A Inner }
+				return Map(&dst, struct{ A string }{A: "x"})
+			},
+			code:     CodeIncompatibleTypes,
+			sentinel: ErrIncompatibleTypes,
+		},
+		{
+			name: "conversion failed",
+			run: func() error {
+				var dst struct{ A int }
+				return Map(&dst, struct {
+					A string `mapconv:"int"`
+				}{A: "x"})
+			},
+			code:     CodeConversionFailed,
+			sentinel: ErrConversionFailed,
+		},
+		{
+			name: "unsupported conversion",
+			run: func() error {
+				var dst struct{ A int }
+				return Map(&dst, struct {
+					A string `mapconv:"complex128"`
+				}{A: "1"})
+			},
+			code:     CodeUnsupportedConversion,
+			sentinel: ErrUnsupportedConversion,
+		},
+		{
+			name: "depth exceeded",
+			run: func() error {
+				var dst Node
+				return MapWithOptions(&dst, Node{Next: &Node{Next: &Node{}}}, WithMaxDepth(1))
+			},
+			code:     CodeDepthExceeded,
+			sentinel: ErrDepthExceeded,
+		},
+		{
+			name: "unmatched field",
+			run: func() error {
+				var dst struct{ A, B int }
+				return MapWithOptions(&dst, struct{ A int }{}, WithStrictMode())
+			},
+			code:     CodeUnmatchedField,
+			sentinel: ErrUnmatchedField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			mappingErr, ok := err.(*MappingError)
+			if !ok {
+				t.Fatalf("expected *MappingError, got %T (%v)", err, err)
+			}
+			if mappingErr.Code != tt.code {
+				t.Errorf("expected code %q, got %q", tt.code, mappingErr.Code)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("expected errors.Is to match %v", tt.sentinel)
+			}
+			if errors.Is(err, ErrFieldNotSettable) {
+				t.Error("expected errors.Is not to match an unrelated sentinel")
+			}
+		})
+	}
+}
+
+func TestMappingError_UnwrapConversionError(t *testing.T) {
+	type Src struct {
+		Age string `mapconv:"int"`
+	}
+	type Dst struct {
+		Age int
+	}
+
+	var dst Dst
+	err := Map(&dst, Src{Age: "abc"})
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("expected *strconv.NumError to be reachable, got %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Error("expected errors.Is to reach strconv.ErrSyntax")
+	}
+}
+
+func TestMappingError_UnwrapConverterError(t *testing.T) {
+	errInvalid := errors.New("invalid money")
+
+	type Src struct{ Total convMoney }
+	type Dst struct{ Total convMoneyDTO }
+
+	var dst Dst
+	err := MapWithOptions(&dst, Src{}, WithConverter(func(convMoney) (convMoneyDTO, error) {
+		return convMoneyDTO{}, errInvalid
+	}))
+
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("expected converter error to be reachable, got %v", err)
+	}
+	if !errors.Is(err, ErrConverterFailed) {
+		t.Errorf("expected ErrConverterFailed, got %v", err)
+	}
+}
+
+func TestMappingErrors_Is(t *testing.T) {
+	src := collectForm{Name: "Alice", Age: "abc"}
+
+	var dst collectFormDst
+	err := MapWithOptions(&dst, src, WithCollectErrors(), WithStrictMode())
+
+	if !errors.Is(err, ErrConversionFailed) {
+		t.Error("expected aggregate to match ErrConversionFailed")
+	}
+	if !errors.Is(err, ErrUnmatchedField) {
+		t.Error("expected aggregate to match ErrUnmatchedField")
+	}
+	if errors.Is(err, ErrDepthExceeded) {
+		t.Error("expected aggregate not to match ErrDepthExceeded")
+	}
+}
@@ -0,0 +1,110 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// factoryFunc returns a new value of the type it was registered for.
+type factoryFunc func() reflect.Value
+
+// RegisterFactory registers a function that creates new values of type T.
+// Registered factories are used by every mapping call made through [Map] and
+// [MapWithOptions]; [Mapper] instances created with [New] take factories
+// through [WithFactory] instead.
+//
+// Whenever the engine allocates a fresh T to map into, it starts from the
+// value returned by fn instead of the zero value: new slice elements, map
+// values, pointer targets and the results of [To] and [MapSlice]. Destination
+// fields without a matching source field, and fields skipped by
+// [WithIgnoreZeroSource], keep the values set by the factory. A factory for a
+// pointer type *T is used when a fresh *T is allocated, and takes precedence
+// over a factory for T.
+//
+// Example:
+//
+//	mapper.RegisterFactory(func() Order {
+//	    return Order{ID: uuid.New(), Status: StatusDraft, Meta: map[string]string{}}
+//	})
+//
+// fn must return a new value on every call; a factory returning the same
+// pointer or map makes the mapped values share it. Registering a factory for
+// a type that already has one replaces it. RegisterFactory is safe for
+// concurrent use, but is typically called during program initialization.
+func RegisterFactory[T any](fn func() T) {
+	t, f := newFactory(fn)
+	registeredFactories.store(t, f)
+}
+
+// WithFactory adds a factory for T for a single mapping call, or for every
+// call of a [Mapper] when passed to [New]. It takes precedence over a factory
+// registered for the same type with [RegisterFactory].
+func WithFactory[T any](fn func() T) Option {
+	t, f := newFactory(fn)
+	return func(c *config) {
+		c.factories = copyMap(c.factories, 1)
+		c.factories[t] = f
+	}
+}
+
+func newFactory[T any](fn func() T) (reflect.Type, factoryFunc) {
+	f := func() reflect.Value {
+		v := fn()
+		return reflect.ValueOf(&v).Elem()
+	}
+	return typeFor[T](), f
+}
+
+// hasFactories reports whether any factory may apply to this call.
+func (c *config) hasFactories() bool {
+	return hasEntries(c.factories, &registeredFactories, c.registered)
+}
+
+// lookupFactory returns the factory for t, preferring per-call factories over
+// registered ones.
+func (c *config) lookupFactory(t reflect.Type) (factoryFunc, bool) {
+	return lookupEntry(c.factories, &registeredFactories, c.registered, t)
+}
+
+// newValue returns a new settable value of type t, initialized by the
+// factory for t if there is one.
+func (c *config) newValue(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	if c.hasFactories() {
+		if f, ok := c.lookupFactory(t); ok {
+			v.Set(f())
+		}
+	}
+	return v
+}
+
+// newPointer returns a new pointer of type ptrType. A non-nil pointer from
+// the factory for ptrType is used as it is; otherwise the pointee is
+// initialized by the factory for the element type if there is one.
+func (c *config) newPointer(ptrType reflect.Type) reflect.Value {
+	if c.hasFactories() {
+		if f, ok := c.lookupFactory(ptrType); ok {
+			if ptr := f(); !ptr.IsNil() {
+				return ptr
+			}
+		}
+		if f, ok := c.lookupFactory(ptrType.Elem()); ok {
+			ptr := reflect.New(ptrType.Elem())
+			ptr.Elem().Set(f())
+			return ptr
+		}
+	}
+	return reflect.New(ptrType.Elem())
+}
+
+// initElements initializes every element of a new slice with the factory
+// for its element type, if there is one.
+func (c *config) initElements(s reflect.Value) {
+	if !c.hasFactories() {
+		return
+	}
+	if f, ok := c.lookupFactory(s.Type().Elem()); ok {
+		for i := 0; i < s.Len(); i++ {
+			s.Index(i).Set(f())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package mapper
+
+import (
+	"testing"
+)
+
+type factoryItemDTO struct {
+	Name string
+}
+
+type factoryItem struct {
+	Name   string
+	Status string
+	Meta   map[string]string
+}
+
+type factoryOrderDTO struct {
+	Main   factoryItemDTO
+	Extra  *factoryItemDTO
+	Items  []factoryItemDTO
+	ByName map[string]factoryItemDTO
+}
+
+type factoryOrder struct {
+	Main   factoryItem
+	Extra  *factoryItem
+	Items  []factoryItem
+	ByName map[string]factoryItem
+}
+
+func newFactoryItem() factoryItem {
+	return factoryItem{Status: "draft", Meta: map[string]string{}}
+}
+
+func TestFactory_FreshValues(t *testing.T) {
+	src := factoryOrderDTO{
+		Main:   factoryItemDTO{Name: "main"},
+		Extra:  &factoryItemDTO{Name: "extra"},
+		Items:  []factoryItemDTO{{Name: "a"}, {Name: "b"}},
+		ByName: map[string]factoryItemDTO{"c": {Name: "c"}},
+	}
+	var dst factoryOrder
+	if err := MapWithOptions(&dst, src, WithFactory(newFactoryItem)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Extra.Status != "draft" || dst.Extra.Name != "extra" || dst.Extra.Meta == nil {
+		t.Errorf("expected pointer target from the factory, got %+v", dst.Extra)
+	}
+	for i, item := range dst.Items {
+		if item.Status != "draft" || item.Meta == nil {
+			t.Errorf("expected slice element %d from the factory, got %+v", i, item)
+		}
+	}
+	if c := dst.ByName["c"]; c.Status != "draft" || c.Name != "c" {
+		t.Errorf("expected map value from the factory, got %+v", c)
+	}
+	dst.Items[0].Meta["k"] = "v"
+	if len(dst.Items[1].Meta) != 0 {
+		t.Error("expected every element to get its own value")
+	}
+
+	// Existing values are mapped into, not replaced
+	if dst.Main.Status != "" {
+		t.Errorf("expected an existing struct field to be left to the source, got %+v", dst.Main)
+	}
+}
+
+func TestFactory_PointerType(t *testing.T) {
+	var calls int
+	ptrFactory := WithFactory(func() *factoryItem {
+		calls++
+		return &factoryItem{Status: "from pointer"}
+	})
+
+	src := factoryOrderDTO{
+		Extra: &factoryItemDTO{Name: "extra"},
+		Items: []factoryItemDTO{{Name: "a"}},
+	}
+	var dst factoryOrder
+	err := MapWithOptions(&dst, src, WithFactory(newFactoryItem), ptrFactory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Extra.Status != "from pointer" || calls != 1 {
+		t.Errorf("expected the pointer factory to take precedence, got %+v", dst.Extra)
+	}
+	if dst.Items[0].Status != "draft" {
+		t.Errorf("expected the value factory for slice elements, got %+v", dst.Items[0])
+	}
+}
+
+func TestFactory_Registered(t *testing.T) {
+	RegisterFactory(newFactoryItem)
+	t.Cleanup(func() {
+		registeredFactories.delete(typeFor[factoryItem]())
+	})
+
+	item, err := To[factoryItem](factoryItemDTO{Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Status != "draft" || item.Name != "a" {
+		t.Errorf("expected To to start from the factory, got %+v", item)
+	}
+
+	ptr, err := To[*factoryItem](factoryItemDTO{Name: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr.Status != "draft" || ptr.Name != "b" {
+		t.Errorf("expected To to start from the factory, got %+v", ptr)
+	}
+
+	items, err := MapSlice[factoryItem]([]factoryItemDTO{{Name: "c"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].Status != "draft" {
+		t.Errorf("expected MapSlice to start from the factory, got %+v", items[0])
+	}
+
+	// Instances take factories through WithFactory only
+	var dst factoryOrder
+	if err := New().Map(&dst, factoryOrderDTO{Items: []factoryItemDTO{{Name: "a"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Items[0].Status != "" {
+		t.Errorf("expected instances to ignore registered factories, got %+v", dst.Items[0])
+	}
+}
+
+func TestFactory_MergeAndPatch(t *testing.T) {
+	type Dst struct {
+		Items []factoryItem `mapkey:"Name"`
+	}
+	dst := Dst{Items: []factoryItem{{Name: "a", Status: "active"}}}
+	src := factoryOrderDTO{Items: []factoryItemDTO{{Name: "a"}, {Name: "b"}}}
+
+	if err := MapWithOptions(&dst, src, WithFactory(newFactoryItem)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Items[0].Status != "active" || dst.Items[1].Status != "draft" {
+		t.Errorf("expected only appended elements to come from the factory, got %+v", dst.Items)
+	}
+
+	var patched factoryOrder
+	err := ApplyMergePatch(&patched, []byte(`{"Extra":{"Name":"x"},"Items":[{"Name":"y"}]}`), WithFactory(newFactoryItem))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patched.Extra.Status != "draft" || patched.Items[0].Status != "draft" {
+		t.Errorf("expected patched values to start from the factory, got %+v", patched)
+	}
+}
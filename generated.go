@@ -0,0 +1,168 @@
+package mapper
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// generatedKey identifies a registered generated mapping function.
+type generatedKey struct {
+	src     reflect.Type
+	dst     reflect.Type
+	tagName string
+}
+
+// generatedMapper is a registered generated mapping function together with
+// the configuration it was generated for.
+type generatedMapper struct {
+	cfg  config
+	call func(dst, src any) error
+	// recursive is set when the types can nest without bound; generated
+	// code does not enforce the maximum depth, so such pairs use the engine
+	recursive bool
+}
+
+var (
+	generatedMappers sync.Map // map[generatedKey]*generatedMapper
+	generatedCount   atomic.Int32
+)
+
+// RegisterGenerated registers a reflection-free mapping function for the
+// source type S and destination type D. It is called from the init function
+// of files produced by the mapper-gen command and is rarely needed by hand.
+//
+// The options describe the configuration the function was generated for.
+// Registered functions are process-global: [Map], [MapWithOptions] and the
+// [Mapper] methods dispatch to the registered function only when
+// they are called with the same type pair and an equivalent configuration;
+// otherwise the reflection-based engine is used.
+//
+// Example (as emitted by mapper-gen):
+//
+//	func init() {
+//	    mapper.RegisterGenerated(MapUserDTOToUser, mapper.WithTagName("map"))
+//	}
+//
+// Functions for recursive types, such as a struct with a pointer to its own
+// type, are never dispatched to, because generated code does not enforce the
+// maximum nesting depth and would overflow the stack on cyclic values.
+// Registering a function for a pair and tag name that is already registered
+// replaces the previous function.
+func RegisterGenerated[D, S any](fn func(dst *D, src *S) error, opts ...Option) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	gen := &generatedMapper{
+		cfg:       cfg,
+		recursive: isRecursive(typeFor[S]()) || isRecursive(typeFor[D]()),
+		call: func(dst, src any) error {
+			d := dst.(*D)
+			if s, ok := src.(*S); ok {
+				return fn(d, s)
+			}
+			s := src.(S)
+			return fn(d, &s)
+		},
+	}
+
+	key := generatedKey{
+		src:     typeFor[S](),
+		dst:     typeFor[D](),
+		tagName: cfg.tagName,
+	}
+
+	if _, loaded := generatedMappers.Swap(key, gen); !loaded {
+		generatedCount.Add(1)
+	}
+}
+
+// typeFor returns the reflect.Type of T, including interface types.
+func typeFor[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// lookupGenerated returns the generated mapper registered for the type pair
+// if its configuration is equivalent to cfg.
+func lookupGenerated(srcType, dstType reflect.Type, cfg *config) (*generatedMapper, bool) {
+	// Generated code cannot apply converters, factories, implementations or
+	// discriminators, fill a change report or check and pass on a context,
+	// so any of them disables dispatch
+	if generatedCount.Load() == 0 || cfg.hasConverters() || cfg.hasFactories() || cfg.hasImplementations() || cfg.hasDiscriminators() || cfg.report != nil || cfg.hasContext() {
+		return nil, false
+	}
+
+	v, ok := generatedMappers.Load(generatedKey{src: srcType, dst: dstType, tagName: cfg.tagName})
+	if !ok {
+		return nil, false
+	}
+
+	gen := v.(*generatedMapper)
+	if gen.recursive || !gen.cfg.sameSemantics(cfg) {
+		return nil, false
+	}
+	return gen, true
+}
+
+// isRecursive reports whether a value of type t can contain a value of one of
+// the composite types it is made of, so that values can nest without bound or
+// be cyclic.
+func isRecursive(t reflect.Type) bool {
+	return reaches(t, make(map[reflect.Type]bool))
+}
+
+// reaches walks the types t is made of; visiting holds the types on the
+// current path, mapped to true, and finished types, mapped to false.
+func reaches(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return false
+	}
+	if onPath, seen := visiting[t]; seen {
+		return onPath
+	}
+
+	visiting[t] = true
+	found := false
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField() && !found; i++ {
+			if f := t.Field(i); f.IsExported() {
+				found = reaches(f.Type, visiting)
+			}
+		}
+	} else {
+		found = reaches(t.Elem(), visiting)
+	}
+	visiting[t] = false
+	return found
+}
+
+// sameSemantics reports whether mapping with c and other produces identical results.
+func (c *config) sameSemantics(other *config) bool {
+	return c.tagName == other.tagName &&
+		c.ignoreZeroSource == other.ignoreZeroSource &&
+		c.strictMode == other.strictMode &&
+		sameTypeSet(c.strictTypes, other.strictTypes) &&
+		c.strictSource == other.strictSource &&
+		c.maxDepth == other.maxDepth &&
+		c.collectErrors == other.collectErrors &&
+		c.maxErrors == other.maxErrors &&
+		c.merge == other.merge &&
+		c.pointers == other.pointers &&
+		c.preserveRefs == other.preserveRefs
+}
+
+func sameTypeSet(a, b map[reflect.Type]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for t := range a {
+		if _, ok := b[t]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,251 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type genSrc struct {
+	Name string
+}
+
+type genDst struct {
+	Name string
+	Via  string
+}
+
+func registerGenTestMapper(t *testing.T, opts ...Option) {
+	t.Helper()
+	RegisterGenerated(func(dst *genDst, src *genSrc) error {
+		dst.Name = src.Name
+		dst.Via = "generated"
+		return nil
+	}, opts...)
+	t.Cleanup(func() {
+		cfg := defaultConfig()
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		generatedMappers.Delete(generatedKey{src: typeFor[genSrc](), dst: typeFor[genDst](), tagName: cfg.tagName})
+		generatedCount.Add(-1)
+	})
+}
+
+func TestGenerated_Dispatch(t *testing.T) {
+	registerGenTestMapper(t)
+
+	var dst genDst
+	if err := Map(&dst, genSrc{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "generated" || dst.Name != "Alice" {
+		t.Errorf("expected generated function to be used, got %+v", dst)
+	}
+
+	dst = genDst{}
+	if err := Map(&dst, &genSrc{Name: "Bob"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "generated" || dst.Name != "Bob" {
+		t.Errorf("expected generated function to be used for pointer source, got %+v", dst)
+	}
+}
+
+func TestGenerated_Instances(t *testing.T) {
+	registerGenTestMapper(t)
+	registerTestConverter(t, func(n int) (int, error) { return n, nil })
+
+	// Registered converters apply to the package-level functions only, so
+	// they do not keep instances from using generated functions
+	var dst genDst
+	if err := New().Map(&dst, genSrc{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "generated" {
+		t.Errorf("expected instances to use generated functions, got %+v", dst)
+	}
+
+	dst = genDst{}
+	if err := Map(&dst, genSrc{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "" {
+		t.Errorf("expected the registered converter to bypass generated functions, got %+v", dst)
+	}
+
+	dst = genDst{}
+	m := New(WithConverter(func(n int) (int, error) { return n, nil }))
+	if err := m.Map(&dst, genSrc{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "" {
+		t.Errorf("expected instance converters to bypass generated functions, got %+v", dst)
+	}
+}
+
+func TestGenerated_OptionsMustMatch(t *testing.T) {
+	registerGenTestMapper(t)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"different tag name", []Option{WithTagName("json")}, false},
+		{"ignore zero source", []Option{WithIgnoreZeroSource()}, false},
+		{"strict mode", []Option{WithStrictMode()}, true}, // Via has no source field
+		{"max depth", []Option{WithMaxDepth(10)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst genDst
+			err := MapWithOptions(&dst, genSrc{Name: "Alice"}, tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected reflection engine error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dst.Via != "" {
+				t.Error("expected reflection engine to be used")
+			}
+			if dst.Name != "Alice" {
+				t.Errorf("expected Name = 'Alice', got %q", dst.Name)
+			}
+		})
+	}
+}
+
+func TestGenerated_MaxErrorsMustMatch(t *testing.T) {
+	registerGenTestMapper(t, WithMaxErrors(1))
+
+	var dst genDst
+	if err := MapWithOptions(&dst, genSrc{Name: "Alice"}, WithMaxErrors(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "generated" {
+		t.Error("expected generated function to be used with the same error limit")
+	}
+
+	dst = genDst{}
+	if err := MapWithOptions(&dst, genSrc{Name: "Alice"}, WithMaxErrors(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "" {
+		t.Error("expected reflection engine to be used with a different error limit")
+	}
+}
+
+func TestGenerated_RegisteredForTagName(t *testing.T) {
+	registerGenTestMapper(t, WithTagName("json"))
+
+	var dst genDst
+	if err := MapWithOptions(&dst, genSrc{Name: "Alice"}, WithTagName("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "generated" {
+		t.Error("expected generated function to be used for matching tag name")
+	}
+
+	dst = genDst{}
+	if err := Map(&dst, genSrc{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Via != "" {
+		t.Error("expected reflection engine for the default tag name")
+	}
+}
+
+func TestGenerated_ErrorsPropagate(t *testing.T) {
+	RegisterGenerated(func(dst *genDst, src *genSrc) error {
+		return &MappingError{SrcType: "mapper.genSrc", DstType: "mapper.genDst", FieldPath: "Name", Reason: "boom"}
+	})
+	t.Cleanup(func() {
+		generatedMappers.Delete(generatedKey{src: typeFor[genSrc](), dst: typeFor[genDst](), tagName: "map"})
+		generatedCount.Add(-1)
+	})
+
+	var dst genDst
+	err := Map(&dst, genSrc{Name: "Alice"})
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T", err)
+	}
+	if mappingErr.FieldPath != "Name" || mappingErr.Reason != "boom" {
+		t.Errorf("unexpected error: %v", mappingErr)
+	}
+}
+
+type genNode struct {
+	Name string
+	Next *genNode
+}
+
+type genNodeDTO struct {
+	Name string
+	Next *genNodeDTO
+}
+
+func TestGenerated_RecursiveTypesUseEngine(t *testing.T) {
+	// Mirrors the code mapper-gen emits for the pair: no depth limit
+	var calls int
+	var mapNode func(dst *genNodeDTO, src *genNode) error
+	mapNode = func(dst *genNodeDTO, src *genNode) error {
+		if calls++; calls > 1000 {
+			t.Fatal("expected recursive pairs not to dispatch to generated code")
+		}
+		dst.Name = src.Name
+		if src.Next != nil {
+			dst.Next = new(genNodeDTO)
+			return mapNode(dst.Next, src.Next)
+		}
+		return nil
+	}
+	RegisterGenerated(mapNode)
+	t.Cleanup(func() {
+		generatedMappers.Delete(generatedKey{src: typeFor[genNode](), dst: typeFor[genNodeDTO](), tagName: "map"})
+		generatedCount.Add(-1)
+	})
+
+	cyclic := &genNode{Name: "a"}
+	cyclic.Next = cyclic
+
+	var dst genNodeDTO
+	err := Map(&dst, cyclic)
+	if !errors.Is(err, ErrDepthExceeded) {
+		t.Errorf("expected the engine's depth error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected the generated function not to be called, got %d calls", calls)
+	}
+}
+
+func TestIsRecursive(t *testing.T) {
+	type Tree struct{ Children []Tree }
+	type List []List
+	type Flat struct {
+		Items []genSrc
+		ByID  map[string]*genSrc
+	}
+
+	tests := []struct {
+		typ  any
+		want bool
+	}{
+		{genNode{}, true},
+		{Tree{}, true},
+		{List{}, true},
+		{Flat{}, false},
+		{genDst{}, false},
+	}
+	for _, tt := range tests {
+		if got := isRecursive(reflect.TypeOf(tt.typ)); got != tt.want {
+			t.Errorf("isRecursive(%T) = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// BeforeMapper is implemented by destination types that need to prepare
+// themselves before their fields are mapped, such as resetting derived state.
+//
+// BeforeMap is called with the source struct value, at every level a struct
+// is mapped: the top-level destination, nested struct fields, slice elements,
+// map values and pointer elements. The method may have a pointer receiver.
+type BeforeMapper interface {
+	BeforeMap(src any) error
+}
+
+// AfterMapper is implemented by destination types that derive fields once
+// their fields are mapped, such as recomputing totals or normalizing values.
+//
+// AfterMap is called with the source struct value after the fields of the
+// destination are mapped, at every level a struct is mapped. Nested structs
+// are complete when the AfterMap of their parent runs.
+//
+// Example:
+//
+//	func (o *Order) AfterMap(src any) error {
+//	    o.Total = 0
+//	    for _, line := range o.Lines {
+//	        o.Total += line.Price * line.Qty
+//	    }
+//	    return nil
+//	}
+type AfterMapper interface {
+	AfterMap(src any) error
+}
+
+// SourceBeforeMapper is implemented by source types that validate or prepare
+// a mapping before it starts. BeforeMapTo is called with a pointer to the
+// destination struct, before the destination's BeforeMap.
+type SourceBeforeMapper interface {
+	BeforeMapTo(dst any) error
+}
+
+// SourceAfterMapper is implemented by source types that complete a mapping.
+// AfterMapTo is called with a pointer to the destination struct, after the
+// destination's AfterMap.
+type SourceAfterMapper interface {
+	AfterMapTo(dst any) error
+}
+
+// BeforeMapperContext is like [BeforeMapper] for a hook that also receives
+// the context of the mapping call: the ctx passed to [MapContext], or
+// context.Background for calls made without one. It is called instead of
+// BeforeMap when a type implements both.
+type BeforeMapperContext interface {
+	BeforeMapContext(ctx context.Context, src any) error
+}
+
+// AfterMapperContext is like [AfterMapper] for a hook that also receives the
+// context of the mapping call, and is called instead of AfterMap.
+type AfterMapperContext interface {
+	AfterMapContext(ctx context.Context, src any) error
+}
+
+// SourceBeforeMapperContext is like [SourceBeforeMapper] for a hook that also
+// receives the context of the mapping call, and is called instead of
+// BeforeMapTo.
+type SourceBeforeMapperContext interface {
+	BeforeMapToContext(ctx context.Context, dst any) error
+}
+
+// SourceAfterMapperContext is like [SourceAfterMapper] for a hook that also
+// receives the context of the mapping call, and is called instead of
+// AfterMapTo.
+type SourceAfterMapperContext interface {
+	AfterMapToContext(ctx context.Context, dst any) error
+}
+
+// hookSet records which mapping hooks a source and destination type pair
+// implements. It is resolved once per plan.
+type hookSet uint8
+
+const (
+	hookSrcBefore hookSet = 1 << iota
+	hookDstBefore
+	hookDstAfter
+	hookSrcAfter
+)
+
+var (
+	beforeMapperType              = typeFor[BeforeMapper]()
+	afterMapperType               = typeFor[AfterMapper]()
+	sourceBeforeMapperType        = typeFor[SourceBeforeMapper]()
+	sourceAfterMapperType         = typeFor[SourceAfterMapper]()
+	beforeMapperContextType       = typeFor[BeforeMapperContext]()
+	afterMapperContextType        = typeFor[AfterMapperContext]()
+	sourceBeforeMapperContextType = typeFor[SourceBeforeMapperContext]()
+	sourceAfterMapperContextType  = typeFor[SourceAfterMapperContext]()
+)
+
+// hooksFor returns the hooks implemented by srcType and dstType, including
+// methods with pointer receivers and context-aware variants.
+func hooksFor(srcType, dstType reflect.Type) hookSet {
+	var h hookSet
+	srcPtr := reflect.PointerTo(srcType)
+	dstPtr := reflect.PointerTo(dstType)
+	if srcPtr.Implements(sourceBeforeMapperType) || srcPtr.Implements(sourceBeforeMapperContextType) {
+		h |= hookSrcBefore
+	}
+	if dstPtr.Implements(beforeMapperType) || dstPtr.Implements(beforeMapperContextType) {
+		h |= hookDstBefore
+	}
+	if dstPtr.Implements(afterMapperType) || dstPtr.Implements(afterMapperContextType) {
+		h |= hookDstAfter
+	}
+	if srcPtr.Implements(sourceAfterMapperType) || srcPtr.Implements(sourceAfterMapperContextType) {
+		h |= hookSrcAfter
+	}
+	return h
+}
+
+// runBeforeHooks calls the source's BeforeMapTo and the destination's
+// BeforeMap, in that order, for the hooks of plan. Context-aware variants
+// receive ctx.
+func runBeforeHooks(ctx context.Context, plan *structPlan, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string) error {
+	if plan.hooks&hookSrcBefore != 0 {
+		switch recv := hookReceiver(src).(type) {
+		case SourceBeforeMapperContext:
+			if err := recv.BeforeMapToContext(ctx, dst.Addr().Interface()); err != nil {
+				return hookError("BeforeMapToContext", err, srcStructType, dstStructType, fieldPath)
+			}
+		case SourceBeforeMapper:
+			if err := recv.BeforeMapTo(dst.Addr().Interface()); err != nil {
+				return hookError("BeforeMapTo", err, srcStructType, dstStructType, fieldPath)
+			}
+		}
+	}
+	if plan.hooks&hookDstBefore != 0 {
+		switch recv := dst.Addr().Interface().(type) {
+		case BeforeMapperContext:
+			if err := recv.BeforeMapContext(ctx, src.Interface()); err != nil {
+				return hookError("BeforeMapContext", err, srcStructType, dstStructType, fieldPath)
+			}
+		case BeforeMapper:
+			if err := recv.BeforeMap(src.Interface()); err != nil {
+				return hookError("BeforeMap", err, srcStructType, dstStructType, fieldPath)
+			}
+		}
+	}
+	return nil
+}
+
+// runAfterHooks calls the destination's AfterMap and the source's
+// AfterMapTo, in that order, for the hooks of plan. Context-aware variants
+// receive ctx.
+func runAfterHooks(ctx context.Context, plan *structPlan, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string) error {
+	if plan.hooks&hookDstAfter != 0 {
+		switch recv := dst.Addr().Interface().(type) {
+		case AfterMapperContext:
+			if err := recv.AfterMapContext(ctx, src.Interface()); err != nil {
+				return hookError("AfterMapContext", err, srcStructType, dstStructType, fieldPath)
+			}
+		case AfterMapper:
+			if err := recv.AfterMap(src.Interface()); err != nil {
+				return hookError("AfterMap", err, srcStructType, dstStructType, fieldPath)
+			}
+		}
+	}
+	if plan.hooks&hookSrcAfter != 0 {
+		switch recv := hookReceiver(src).(type) {
+		case SourceAfterMapperContext:
+			if err := recv.AfterMapToContext(ctx, dst.Addr().Interface()); err != nil {
+				return hookError("AfterMapToContext", err, srcStructType, dstStructType, fieldPath)
+			}
+		case SourceAfterMapper:
+			if err := recv.AfterMapTo(dst.Addr().Interface()); err != nil {
+				return hookError("AfterMapTo", err, srcStructType, dstStructType, fieldPath)
+			}
+		}
+	}
+	return nil
+}
+
+// hookReceiver returns a pointer to src so hooks with pointer receivers can
+// be called. Values that are not addressable, such as map values, are copied.
+func hookReceiver(src reflect.Value) any {
+	if src.CanAddr() {
+		return src.Addr().Interface()
+	}
+	ptr := reflect.New(src.Type())
+	ptr.Elem().Set(src)
+	return ptr.Interface()
+}
+
+func hookError(hook string, err error, srcStructType, dstStructType reflect.Type, fieldPath string) error {
+	return &MappingError{
+		SrcType:   srcStructType.String(),
+		DstType:   dstStructType.String(),
+		FieldPath: fieldPath,
+		Reason:    hook + " hook failed: " + err.Error(),
+		Code:      CodeHookFailed,
+		Err:       err,
+	}
+}
@@ -0,0 +1,268 @@
+package mapper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type hookLineDTO struct {
+	Price int
+	Qty   int
+}
+
+type hookLine struct {
+	Price    int
+	Qty      int
+	Subtotal int
+	calls    []string
+}
+
+func (l *hookLine) BeforeMap(src any) error {
+	l.calls = append(l.calls, "before")
+	if _, ok := src.(hookLineDTO); !ok {
+		return errors.New("unexpected source type")
+	}
+	return nil
+}
+
+func (l *hookLine) AfterMap(src any) error {
+	if l.Qty < 0 {
+		return errors.New("negative quantity")
+	}
+	l.Subtotal = l.Price * l.Qty
+	l.calls = append(l.calls, "after")
+	return nil
+}
+
+type hookOrderDTO struct {
+	Lines  []hookLineDTO
+	Gift   hookLineDTO
+	Extra  *hookLineDTO
+	ByCode map[string]hookLineDTO
+}
+
+type hookOrder struct {
+	Lines  []hookLine
+	Gift   hookLine
+	Extra  *hookLine
+	ByCode map[string]hookLine
+	Total  int
+}
+
+func (o *hookOrder) AfterMap(src any) error {
+	o.Total = 0
+	for _, l := range o.Lines {
+		o.Total += l.Subtotal
+	}
+	return nil
+}
+
+type hookSource struct {
+	Name string
+	log  *[]string
+}
+
+func (s hookSource) BeforeMapTo(dst any) error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	*s.log = append(*s.log, "src before "+strings.TrimPrefix(typeOf(dst), "*mapper."))
+	return nil
+}
+
+func (s *hookSource) AfterMapTo(dst any) error {
+	*s.log = append(*s.log, "src after")
+	return nil
+}
+
+type hookTarget struct {
+	Name string
+	log  *[]string
+}
+
+func (t *hookTarget) BeforeMap(src any) error {
+	t.log = src.(hookSource).log
+	*t.log = append(*t.log, "dst before")
+	return nil
+}
+
+func (t *hookTarget) AfterMap(src any) error {
+	*t.log = append(*t.log, "dst after "+t.Name)
+	return nil
+}
+
+func TestHooks_EveryNestingLevel(t *testing.T) {
+	src := hookOrderDTO{
+		Lines:  []hookLineDTO{{Price: 2, Qty: 3}, {Price: 5, Qty: 1}},
+		Gift:   hookLineDTO{Price: 1, Qty: 1},
+		Extra:  &hookLineDTO{Price: 4, Qty: 2},
+		ByCode: map[string]hookLineDTO{"a": {Price: 3, Qty: 3}},
+	}
+	var dst hookOrder
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Lines[0].Subtotal != 6 || dst.Lines[1].Subtotal != 5 {
+		t.Errorf("expected slice elements to run AfterMap, got %+v", dst.Lines)
+	}
+	if dst.Gift.Subtotal != 1 || dst.Extra.Subtotal != 8 || dst.ByCode["a"].Subtotal != 9 {
+		t.Errorf("expected nested structs, pointers and map values to run AfterMap, got %+v", dst)
+	}
+	if dst.Total != 11 {
+		t.Errorf("expected parent AfterMap to see mapped children, got Total %d", dst.Total)
+	}
+	if got := strings.Join(dst.Gift.calls, ","); got != "before,after" {
+		t.Errorf("expected BeforeMap then AfterMap, got %s", got)
+	}
+}
+
+func TestHooks_Order(t *testing.T) {
+	var log []string
+	var dst hookTarget
+	if err := Map(&dst, hookSource{Name: "a", log: &log}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "src before hookTarget,dst before,dst after a,src after"
+	if got := strings.Join(log, ","); got != want {
+		t.Errorf("expected hooks %q, got %q", want, got)
+	}
+}
+
+func TestHooks_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		src  hookOrderDTO
+		path string
+	}{
+		{"slice element", hookOrderDTO{Lines: []hookLineDTO{{Price: 2, Qty: 3}, {Price: 5, Qty: -1}}}, "Lines[1]"},
+		{"nested struct", hookOrderDTO{Gift: hookLineDTO{Price: 1, Qty: -1}}, "Gift"},
+		{"pointer element", hookOrderDTO{Extra: &hookLineDTO{Price: 4, Qty: -1}}, "Extra"},
+		{"map value", hookOrderDTO{ByCode: map[string]hookLineDTO{"a": {Qty: -1}}}, "ByCode[a]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst hookOrder
+			err := Map(&dst, tt.src)
+
+			var mappingErr *MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("expected *MappingError, got %v", err)
+			}
+			if mappingErr.FieldPath != tt.path || mappingErr.Code != CodeHookFailed {
+				t.Errorf("expected hook error at %q, got %q (%s)", tt.path, mappingErr.FieldPath, mappingErr.Code)
+			}
+			if !errors.Is(err, ErrHookFailed) || mappingErr.Reason != "AfterMap hook failed: negative quantity" {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHooks_SourceBeforeMapToStopsMapping(t *testing.T) {
+	var log []string
+	var dst hookTarget
+	err := Map(&dst, hookSource{log: &log})
+	if !errors.Is(err, ErrHookFailed) || !strings.Contains(err.Error(), "BeforeMapTo hook failed: name is required") {
+		t.Fatalf("expected BeforeMapTo error, got %v", err)
+	}
+	if len(log) != 0 {
+		t.Errorf("expected no other hooks to run, got %v", log)
+	}
+}
+
+func TestHooks_CollectErrors(t *testing.T) {
+	src := hookOrderDTO{
+		Lines: []hookLineDTO{{Price: 2, Qty: -1}, {Price: 5, Qty: 1}},
+		Gift:  hookLineDTO{Price: 1, Qty: -1},
+	}
+
+	var dst hookOrder
+	err := MapWithOptions(&dst, src, WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 collected errors, got %v", err)
+	}
+	if errs[0].FieldPath != "Lines[0]" || errs[1].FieldPath != "Gift" {
+		t.Errorf("unexpected paths: %q, %q", errs[0].FieldPath, errs[1].FieldPath)
+	}
+	if dst.Total != 5 {
+		t.Errorf("expected the parent AfterMap to run after collected errors, got Total %d", dst.Total)
+	}
+}
+
+type hookCtxKey struct{}
+
+type hookCtxSource struct {
+	Name string
+	log  *[]string
+}
+
+func (s hookCtxSource) BeforeMapToContext(ctx context.Context, dst any) error {
+	tenant, _ := ctx.Value(hookCtxKey{}).(string)
+	*s.log = append(*s.log, "src before "+tenant)
+	return nil
+}
+
+func (s hookCtxSource) AfterMapToContext(ctx context.Context, dst any) error {
+	*s.log = append(*s.log, "src after "+ctx.Value(hookCtxKey{}).(string))
+	return nil
+}
+
+type hookCtxTarget struct {
+	Name string
+	log  []string
+}
+
+func (t *hookCtxTarget) BeforeMap(src any) error {
+	t.log = append(t.log, "plain before")
+	return nil
+}
+
+func (t *hookCtxTarget) BeforeMapContext(ctx context.Context, src any) error {
+	if ctx.Value(hookCtxKey{}) == nil {
+		return errors.New("missing tenant")
+	}
+	t.log = append(t.log, "before "+ctx.Value(hookCtxKey{}).(string))
+	return nil
+}
+
+func (t *hookCtxTarget) AfterMapContext(ctx context.Context, src any) error {
+	t.log = append(t.log, "after "+ctx.Value(hookCtxKey{}).(string))
+	return nil
+}
+
+func TestHooks_Context(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hookCtxKey{}, "acme")
+
+	var srcLog []string
+	type Src struct{ Item hookCtxSource }
+	type Dst struct{ Item hookCtxTarget }
+
+	var dst Dst
+	if err := MapContext(ctx, &dst, Src{Item: hookCtxSource{Name: "a", log: &srcLog}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"before acme", "after acme"}; !reflect.DeepEqual(dst.Item.log, want) {
+		t.Errorf("expected the context-aware hooks only, got %v", dst.Item.log)
+	}
+	if want := []string{"src before acme", "src after acme"}; !reflect.DeepEqual(srcLog, want) {
+		t.Errorf("expected the source hooks to receive the context, got %v", srcLog)
+	}
+
+	// Calls without a context pass context.Background
+	err := Map(&dst, Src{Item: hookCtxSource{Name: "a", log: &srcLog}})
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || !errors.Is(err, ErrHookFailed) {
+		t.Fatalf("expected a hook error, got %v", err)
+	}
+	if mappingErr.FieldPath != "Item" || mappingErr.Reason != "BeforeMapContext hook failed: missing tenant" {
+		t.Errorf("unexpected error %+v", mappingErr)
+	}
+}
@@ -0,0 +1,134 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// implKey identifies a source type mapped into a destination interface type.
+type implKey struct {
+	src   reflect.Type
+	iface reflect.Type
+}
+
+// RegisterImplementation registers T as the concrete type built when a value
+// of type S is mapped into a destination of interface type I. Registered
+// implementations are used by every mapping call made through [Map] and
+// [MapWithOptions]; [Mapper] instances created with [New] take them through
+// [WithImplementation] instead.
+//
+// The new T is mapped from the source value with the usual rules, starting
+// from its factory if there is one, and stored in the interface. If only *T
+// implements I, a *T is stored instead.
+//
+// Example:
+//
+//	mapper.RegisterImplementation[Shape, CircleDTO, Circle]()
+//
+//	// ShapeDTO.Shape is any holding a CircleDTO; Drawing.Shape is a Shape
+//	err := mapper.Map(&drawing, drawingDTO)
+//
+// Without a registered implementation, a source value whose type implements
+// I is deep copied into the destination. Registering an implementation for a
+// type pair that already has one replaces it. RegisterImplementation is safe
+// for concurrent use, but is typically called during program initialization.
+func RegisterImplementation[I, S, T any]() {
+	registeredImplementations.store(implKey{src: typeFor[S](), iface: typeFor[I]()}, typeFor[T]())
+}
+
+// WithImplementation adds an implementation for a single mapping call, or for
+// every call of a [Mapper] when passed to [New]. It takes precedence over an
+// implementation registered for the same type pair with
+// [RegisterImplementation].
+func WithImplementation[I, S, T any]() Option {
+	key := implKey{src: typeFor[S](), iface: typeFor[I]()}
+	t := typeFor[T]()
+	return func(c *config) {
+		c.implementations = copyMap(c.implementations, 1)
+		c.implementations[key] = t
+	}
+}
+
+// hasImplementations reports whether any implementation may apply to this call.
+func (c *config) hasImplementations() bool {
+	return hasEntries(c.implementations, &registeredImplementations, c.registered)
+}
+
+// lookupImplementation returns the concrete type built for values of src
+// mapped into the interface type iface, preferring per-call implementations
+// over registered ones.
+func (c *config) lookupImplementation(src, iface reflect.Type) (reflect.Type, bool) {
+	k := implKey{src: src, iface: iface}
+	return lookupEntry(c.implementations, &registeredImplementations, c.registered, k)
+}
+
+// assignInterface assigns a concrete src to the interface destination dst,
+// building the registered implementation for the pair or the type selected by
+// a discriminator rule, or deep copying src.
+func assignInterface(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath, fieldName, convertTo string, cfg *config, depth int) error {
+	sType := src.Type()
+	dType := dst.Type()
+
+	if cfg.hasImplementations() {
+		if impl, ok := cfg.lookupImplementation(sType, dType); ok {
+			return assignImplementation(dst, src, impl, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth)
+		}
+	}
+
+	if cfg.hasDiscriminators() {
+		if d, ok := cfg.lookupDiscriminator(dType); ok {
+			if handled, err := assignDiscriminated(dst, src, d, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth); handled {
+				return err
+			}
+		}
+	}
+
+	if !sType.AssignableTo(dType) {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: buildPath(basePath, fieldName),
+			Reason:    "incompatible field types: " + sType.String() + " -> " + dType.String(),
+			Code:      CodeIncompatibleTypes,
+		}
+	}
+
+	copied, err := cloneDeep(src, srcStructType, cfg, depth)
+	if err != nil {
+		if e, ok := err.(*MappingError); ok {
+			e.DstType = dstStructType.String()
+			e.FieldPath = joinPath(buildPath(basePath, fieldName), e.FieldPath)
+		}
+		return err
+	}
+	dst.Set(copied)
+	return nil
+}
+
+// assignImplementation maps src into a new value of the concrete type impl,
+// or of *impl if only the pointer implements the interface, and stores it in
+// the interface destination dst.
+func assignImplementation(dst, src reflect.Value, impl, srcStructType, dstStructType reflect.Type, basePath, fieldName, convertTo string, cfg *config, depth int) error {
+	var out, target reflect.Value
+	switch dType := dst.Type(); {
+	case impl.Implements(dType):
+		out = cfg.newValue(impl)
+		target = out
+	case reflect.PointerTo(impl).Implements(dType):
+		out = cfg.newPointer(reflect.PointerTo(impl))
+		target = out.Elem()
+	default:
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: buildPath(basePath, fieldName),
+			Reason:    "implementation " + impl.String() + " does not implement " + dType.String(),
+			Code:      CodeIncompatibleTypes,
+		}
+	}
+
+	if err := assignNestedValue(target, src, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth-1); err != nil {
+		return err
+	}
+	dst.Set(out)
+	return nil
+}
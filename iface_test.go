@@ -0,0 +1,267 @@
+package mapper
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type ifaceShape interface {
+	Area() float64
+}
+
+type ifaceCircleDTO struct {
+	Radius string `mapconv:"float64"`
+}
+
+type ifaceCircle struct {
+	Radius float64
+}
+
+func (c ifaceCircle) Area() float64 { return math.Pi * c.Radius * c.Radius }
+
+type ifaceSquareDTO struct {
+	Side float64
+}
+
+type ifaceSquare struct {
+	Side float64
+}
+
+func (s *ifaceSquare) Area() float64 { return s.Side * s.Side }
+
+type ifaceAddressDTO struct {
+	City string
+}
+
+type ifaceAddress struct {
+	City string
+}
+
+type ifaceEventDTO struct {
+	Count   any
+	Address any
+	Ref     any
+	Payload any
+	Meta    map[string]any
+	Values  []any
+}
+
+type ifaceEvent struct {
+	Count   int64
+	Address ifaceAddress
+	Ref     *ifaceAddress
+	Payload any
+	Meta    map[string]any
+	Values  []any
+}
+
+func TestInterface_SourceDynamicValue(t *testing.T) {
+	src := ifaceEventDTO{
+		Count:   int32(3),
+		Address: ifaceAddressDTO{City: "Lisbon"},
+		Ref:     &ifaceAddressDTO{City: "Porto"},
+	}
+
+	var dst ifaceEvent
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Count != 3 {
+		t.Errorf("expected the dynamic int32 to be converted, got %d", dst.Count)
+	}
+	if dst.Address.City != "Lisbon" || dst.Ref == nil || dst.Ref.City != "Porto" {
+		t.Errorf("expected dynamic structs to be mapped field by field, got %+v and %+v", dst.Address, dst.Ref)
+	}
+}
+
+func TestInterface_NilSource(t *testing.T) {
+	dst := ifaceEvent{Count: 7, Address: ifaceAddress{City: "Lisbon"}, Ref: &ifaceAddress{}, Payload: "old"}
+	if err := Map(&dst, ifaceEventDTO{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Count != 0 || dst.Address != (ifaceAddress{}) {
+		t.Errorf("expected a nil interface to zero scalar and struct destinations, got %+v", dst)
+	}
+	if dst.Ref != nil || dst.Payload != nil {
+		t.Errorf("expected a nil interface to clear pointer and interface destinations, got %+v", dst)
+	}
+
+	dst = ifaceEvent{Count: 7, Ref: &ifaceAddress{}}
+	if err := MapWithOptions(&dst, ifaceEventDTO{}, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Count != 7 || dst.Ref == nil {
+		t.Errorf("expected WithIgnoreZeroSource to skip nil interfaces, got %+v", dst)
+	}
+}
+
+func TestInterface_SourceIncompatible(t *testing.T) {
+	var dst ifaceEvent
+	err := Map(&dst, ifaceEventDTO{Count: "three"})
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		t.Fatalf("expected *MappingError, got %v", err)
+	}
+	if mappingErr.FieldPath != "Count" || !errors.Is(err, ErrIncompatibleTypes) {
+		t.Errorf("expected incompatible types at Count, got %v", err)
+	}
+	if mappingErr.Reason != "incompatible field types: string -> int64" {
+		t.Errorf("expected the dynamic type in the reason, got %q", mappingErr.Reason)
+	}
+}
+
+func TestInterface_DestinationDeepCopy(t *testing.T) {
+	tags := []string{"a"}
+	src := ifaceEventDTO{
+		Payload: map[string]any{"tags": tags},
+		Meta:    map[string]any{"addr": &ifaceAddress{City: "Faro"}},
+		Values:  []any{1, []string{"b"}},
+	}
+
+	var dst ifaceEvent
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags[0] = "changed"
+	src.Meta["addr"].(*ifaceAddress).City = "changed"
+	src.Values[1].([]string)[0] = "changed"
+
+	if got := dst.Payload.(map[string]any)["tags"].([]string)[0]; got != "a" {
+		t.Errorf("expected the payload to be deep copied, got %q", got)
+	}
+	if got := dst.Meta["addr"].(*ifaceAddress).City; got != "Faro" {
+		t.Errorf("expected map values to be deep copied, got %q", got)
+	}
+	if got := dst.Values[1].([]string)[0]; got != "b" || dst.Values[0] != 1 {
+		t.Errorf("expected slice elements to be deep copied, got %v", dst.Values)
+	}
+}
+
+func TestInterface_SameStructDeepCopy(t *testing.T) {
+	type Config struct {
+		Name    string
+		Options any
+	}
+	src := Config{Name: "plugin", Options: map[string]int{"retries": 3}}
+
+	var dst Config
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src.Options.(map[string]int)["retries"] = 0
+
+	if dst.Options.(map[string]int)["retries"] != 3 {
+		t.Error("expected identical structs with interface fields to be deep copied")
+	}
+}
+
+func TestInterface_DestinationNotImplemented(t *testing.T) {
+	type Src struct{ Shape any }
+	type Dst struct{ Shape ifaceShape }
+
+	var dst Dst
+	err := Map(&dst, Src{Shape: ifaceCircleDTO{}})
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		t.Fatalf("expected *MappingError, got %v", err)
+	}
+	if mappingErr.FieldPath != "Shape" || !errors.Is(err, ErrIncompatibleTypes) {
+		t.Errorf("expected incompatible types at Shape, got %v", err)
+	}
+}
+
+func TestInterface_Implementation(t *testing.T) {
+	type Src struct {
+		Main   any
+		Shapes []any
+		ByName map[string]ifaceSquareDTO
+	}
+	type Dst struct {
+		Main   ifaceShape
+		Shapes []ifaceShape
+		ByName map[string]ifaceShape
+	}
+	src := Src{
+		Main:   ifaceCircleDTO{Radius: "1"},
+		Shapes: []any{ifaceSquareDTO{Side: 2}, nil, ifaceCircleDTO{Radius: "2"}},
+		ByName: map[string]ifaceSquareDTO{"s": {Side: 3}},
+	}
+
+	var dst Dst
+	err := MapWithOptions(&dst, src,
+		WithImplementation[ifaceShape, ifaceCircleDTO, ifaceCircle](),
+		WithImplementation[ifaceShape, ifaceSquareDTO, ifaceSquare](),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c, ok := dst.Main.(ifaceCircle); !ok || c.Radius != 1 {
+		t.Errorf("expected a mapped ifaceCircle, got %#v", dst.Main)
+	}
+	if s, ok := dst.Shapes[0].(*ifaceSquare); !ok || s.Side != 2 {
+		t.Errorf("expected a *ifaceSquare when only the pointer implements the interface, got %#v", dst.Shapes[0])
+	}
+	if dst.Shapes[1] != nil || dst.Shapes[2].Area() != 4*math.Pi {
+		t.Errorf("unexpected slice elements %#v", dst.Shapes)
+	}
+	if dst.ByName["s"].Area() != 9 {
+		t.Errorf("expected map values to be built from the implementation, got %#v", dst.ByName["s"])
+	}
+}
+
+func TestInterface_ImplementationErrors(t *testing.T) {
+	type Src struct{ Shapes []any }
+	type Dst struct{ Shapes []ifaceShape }
+
+	var dst Dst
+	err := MapWithOptions(&dst, Src{Shapes: []any{ifaceCircleDTO{Radius: "x"}}},
+		WithImplementation[ifaceShape, ifaceCircleDTO, ifaceCircle]())
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		t.Fatalf("expected *MappingError, got %v", err)
+	}
+	if mappingErr.FieldPath != "Shapes[0].Radius" || !errors.Is(err, ErrConversionFailed) {
+		t.Errorf("expected a conversion error at Shapes[0].Radius, got %v", err)
+	}
+
+	err = MapWithOptions(&dst, Src{Shapes: []any{ifaceSquareDTO{}}},
+		WithImplementation[ifaceShape, ifaceSquareDTO, ifaceAddress]())
+	if !errors.As(err, &mappingErr) || !errors.Is(err, ErrIncompatibleTypes) {
+		t.Fatalf("expected incompatible types, got %v", err)
+	}
+	if mappingErr.Reason != "implementation mapper.ifaceAddress does not implement mapper.ifaceShape" {
+		t.Errorf("unexpected reason %q", mappingErr.Reason)
+	}
+}
+
+func TestInterface_RegisteredImplementation(t *testing.T) {
+	RegisterImplementation[ifaceShape, ifaceCircleDTO, ifaceCircle]()
+	t.Cleanup(func() {
+		registeredImplementations.delete(implKey{src: typeFor[ifaceCircleDTO](), iface: typeFor[ifaceShape]()})
+	})
+
+	type Src struct{ Shape ifaceCircleDTO }
+	type Dst struct{ Shape ifaceShape }
+
+	var dst Dst
+	if err := Map(&dst, Src{Shape: ifaceCircleDTO{Radius: "2"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c, ok := dst.Shape.(ifaceCircle); !ok || c.Radius != 2 {
+		t.Errorf("expected the registered implementation, got %#v", dst.Shape)
+	}
+
+	// Instances take implementations through WithImplementation only
+	err := New().Map(&dst, Src{Shape: ifaceCircleDTO{Radius: "2"}})
+	if !errors.Is(err, ErrIncompatibleTypes) {
+		t.Errorf("expected instances to ignore registered implementations, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package mapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Mapper is a reusable mapper with its own default options and caches.
+// Instances are isolated from each other and from the package-level
+// functions, so different parts of a program can map with different rules
+// side by side.
+//
+// Converters, factories, implementations and discriminators registered with
+// [RegisterConverter], [RegisterFactory], [RegisterImplementation] and
+// [RegisterDiscriminator] are process-global and apply to the package-level
+// functions only; instances take them as options passed to [New]. Functions
+// registered with [RegisterGenerated] are process-global too and are used by
+// instances as well, since they map exactly like the engine.
+//
+// A Mapper is safe for concurrent use. The zero value is not usable; create
+// instances with [New].
+type Mapper struct {
+	cfg       config
+	metaCache sync.Map // map[cacheKey]*structMeta
+	planCache sync.Map // map[planKey]*structPlan
+}
+
+// defaultMapper backs [Map], [MapWithOptions] and the other package-level
+// functions.
+var defaultMapper = newDefaultMapper()
+
+// Registries filled by the Register functions, consulted by calls made
+// through defaultMapper.
+var (
+	registeredConverters      registry[converterKey, converterFunc]
+	registeredFactories       registry[reflect.Type, factoryFunc]
+	registeredImplementations registry[implKey, reflect.Type]
+	registeredDiscriminators  registry[reflect.Type, *discriminator]
+)
+
+func newDefaultMapper() *Mapper {
+	m := New()
+	m.cfg.registered = true
+	return m
+}
+
+// New returns a Mapper whose calls start from the given options.
+// Options passed to [Mapper.MapWithOptions] are applied on top of them.
+//
+// Example:
+//
+//	apiMapper := mapper.New(
+//	    mapper.WithTagName("json"),
+//	    mapper.WithStrictMode(),
+//	    mapper.WithConverter(func(id uuid.UUID) (string, error) {
+//	        return id.String(), nil
+//	    }),
+//	)
+//
+//	err := apiMapper.Map(&resp, user)
+func New(opts ...Option) *Mapper {
+	m := &Mapper{cfg: defaultConfig()}
+	for _, opt := range opts {
+		opt(&m.cfg)
+	}
+	m.cfg.mapper = m
+	return m
+}
+
+// Map copies fields from src to dst using the options the Mapper was created
+// with. See [Map] for the mapping rules.
+func (m *Mapper) Map(dst any, src any) error {
+	return m.MapWithOptions(dst, src)
+}
+
+// MapWithOptions copies fields from src to dst using the options the Mapper
+// was created with, followed by opts. See [MapWithOptions] for details.
+func (m *Mapper) MapWithOptions(dst any, src any, opts ...Option) error {
+	if len(opts) == 0 {
+		// Calling options through &cfg moves cfg to the heap, so calls
+		// without options copy the instance config on the stack instead
+		cfg := m.cfg
+		return runMapping(dst, src, &cfg)
+	}
+	cfg := m.callConfig(opts)
+	return runMapping(dst, src, &cfg)
+}
+
+// callConfig returns the configuration for a single call: the instance
+// options followed by opts.
+func (m *Mapper) callConfig(opts []Option) config {
+	cfg := m.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
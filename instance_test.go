@@ -0,0 +1,135 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMapper_DefaultOptions(t *testing.T) {
+	type Src struct {
+		UserName string `json:"Name"`
+	}
+	type Dst struct {
+		Name  string
+		Email string
+	}
+
+	m := New(WithTagName("json"))
+
+	var dst Dst
+	if err := m.Map(&dst, Src{UserName: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "Alice" {
+		t.Errorf("expected instance tag name to be used, got %+v", dst)
+	}
+
+	// Per-call options are applied on top of the instance options
+	err := m.MapWithOptions(&dst, Src{UserName: "Bob"}, WithStrictMode())
+	if err == nil {
+		t.Fatal("expected strict mode error, got nil")
+	}
+	if mappingErr, ok := err.(*MappingError); !ok || mappingErr.FieldPath != "Email" {
+		t.Errorf("expected strict mode error for Email, got %v", err)
+	}
+
+	// Per-call options do not change the instance
+	if err := m.Map(&dst, Src{UserName: "Carol"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "Carol" {
+		t.Errorf("expected Carol, got %q", dst.Name)
+	}
+}
+
+func TestMapper_ConvertersAreIsolated(t *testing.T) {
+	type Src struct{ Name string }
+	type Dst struct{ Name string }
+
+	upper := New(WithConverter(func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	}))
+	plain := New()
+
+	registerTestConverter(t, func(s string) (string, error) {
+		return "global", nil
+	})
+
+	var dst Dst
+	if err := upper.Map(&dst, Src{Name: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "ALICE" {
+		t.Errorf("expected instance converter, got %q", dst.Name)
+	}
+
+	if err := plain.Map(&dst, Src{Name: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "alice" {
+		t.Errorf("expected registered converter not to affect instances, got %q", dst.Name)
+	}
+
+	if err := Map(&dst, Src{Name: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "global" {
+		t.Errorf("expected registered converter for package-level Map, got %q", dst.Name)
+	}
+}
+
+func TestMapper_CachesAreIsolated(t *testing.T) {
+	type Src struct{ Name string }
+	type Dst struct{ Name string }
+
+	m := New()
+	var dst Dst
+	if err := m.Map(&dst, Src{Name: "Alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := planKey{src: reflect.TypeOf(Src{}), dst: reflect.TypeOf(Dst{}), tagName: "map"}
+	if _, ok := m.planCache.Load(key); !ok {
+		t.Error("expected plan to be cached on the instance")
+	}
+	if _, ok := defaultMapper.planCache.Load(key); ok {
+		t.Error("expected instance mapping not to populate the default cache")
+	}
+	if _, ok := New().planCache.Load(key); ok {
+		t.Error("expected a new instance to start with an empty cache")
+	}
+}
+
+func TestMapper_ConcurrentUse(t *testing.T) {
+	type Src struct {
+		Name  string
+		Items []int
+	}
+	type Dst struct {
+		Name  string
+		Items []int64
+	}
+
+	m := New(WithMaxDepth(8))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dst Dst
+			errs <- m.Map(&dst, Src{Name: "Alice", Items: []int{1, 2, 3}})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// PatchOp is a single RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	// Op is "add", "remove" or "replace".
+	Op string `json:"op"`
+	// Path is a JSON Pointer (RFC 6901) such as "/address/city" or "/tags/2".
+	Path string `json:"path"`
+	// Value is the new value; it is nil for "remove" operations.
+	Value any `json:"value,omitempty"`
+
+	old any // value replaced or removed by the operation, used by Inverse
+}
+
+// MarshalJSON encodes op as a JSON Patch operation. Unlike the struct tags,
+// it always includes the value of "add" and "replace" operations, even when
+// the value is null.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{op.Op, op.Path, op.Value})
+}
+
+// Patch is an RFC 6902 JSON Patch document. It encodes as a JSON array of
+// operations with encoding/json.
+type Patch []PatchOp
+
+// Inverse returns the patch that undoes p: operations are reversed and each
+// add becomes a remove, each remove an add of the removed value, and each
+// replace a replace with the previous value. Only patches built by
+// [CreatePatch] record the previous values needed to invert them.
+func (p Patch) Inverse() Patch {
+	inverse := make(Patch, 0, len(p))
+	for i := len(p) - 1; i >= 0; i-- {
+		op := p[i]
+		switch op.Op {
+		case "add":
+			inverse = append(inverse, PatchOp{Op: "remove", Path: op.Path, old: op.Value})
+		case "remove":
+			inverse = append(inverse, PatchOp{Op: "add", Path: op.Path, Value: op.old})
+		default:
+			inverse = append(inverse, PatchOp{Op: op.Op, Path: op.Path, Value: op.old, old: op.Value})
+		}
+	}
+	return inverse
+}
+
+// CreatePatch returns the RFC 6902 JSON Patch that turns before into after,
+// such as an entity before and after an update, for audit logs or to send
+// to clients. Each argument may be a struct or a non-nil pointer to one.
+//
+// The structs are compared like [Diff], and every difference becomes an
+// operation whose path is a JSON Pointer naming fields by the configured tag
+// name, ignoring tag options such as ",omitempty", or by the Go field name
+// for untagged fields. Fields tagged "-" are left out. Changed values and
+// pointers that become nil or non-nil are replaced, new slice elements and map
+// entries are added, and missing ones are removed, trailing slice elements
+// from the last one. Values are in the types of before, so the patch encodes
+// with the same JSON names as the structs.
+//
+// Example:
+//
+//	patch, err := mapper.CreatePatch(before, after, mapper.WithTagName("json"))
+//	body, _ := json.Marshal(patch)
+//	// [{"op":"replace","path":"/name","value":"Alicia"},
+//	//  {"op":"add","path":"/tags/2","value":"admin"}]
+//
+//	undo := patch.Inverse()
+//
+// Options apply as in [Diff].
+func CreatePatch(before, after any, opts ...Option) (Patch, error) {
+	return defaultMapper.CreatePatch(before, after, opts...)
+}
+
+// CreatePatch returns the JSON Patch from before to after using the options
+// the Mapper was created with, followed by opts. See [CreatePatch].
+func (m *Mapper) CreatePatch(before, after any, opts ...Option) (Patch, error) {
+	cfg := m.callConfig(opts)
+	d, err := newDiffer(before, after, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.pointer = true
+	if err := d.run(); err != nil {
+		return d.ops, err
+	}
+	return d.ops, nil
+}
+
+// pointerTokens returns the escaped JSON Pointer token of every field of the
+// struct type, keyed by field index. Fields tagged "-" have no token.
+func (d *differ) pointerTokens(t reflect.Type) (map[int]string, error) {
+	meta, err := d.cfg.mapper.getStructMeta(t, d.cfg.tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[int]string, len(meta.Fields))
+	for _, f := range meta.Fields {
+		name, _, _ := strings.Cut(f.Tag, ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		tokens[f.Index[0]] = escapePointerToken(name)
+	}
+	return tokens, nil
+}
+
+// escapePointerToken escapes "~" and "/" in a JSON Pointer reference token.
+func escapePointerToken(s string) string {
+	if !strings.ContainsAny(s, "~/") {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,149 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type patchDocAddress struct {
+	City string `json:"city"`
+}
+
+type patchDoc struct {
+	Name    string            `json:"name,omitempty"`
+	Email   *string           `json:"email"`
+	Address *patchDocAddress  `json:"address"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Notes   []string          `json:"notes"`
+	Count   int
+	Secret  string `json:"-"`
+}
+
+func marshalPatch(t *testing.T, p Patch) string {
+	t.Helper()
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(out)
+}
+
+func TestCreatePatch(t *testing.T) {
+	email := "alice@example.com"
+	before := patchDoc{
+		Name:    "Alice",
+		Email:   &email,
+		Address: &patchDocAddress{City: "Paris"},
+		Tags:    []string{"a", "b", "c", "d"},
+		Labels:  map[string]string{"team": "core", "a/b": "x"},
+		Count:   1,
+		Secret:  "s1",
+	}
+	after := patchDoc{
+		Name:    "Alicia",
+		Address: &patchDocAddress{City: "Rome"},
+		Tags:    []string{"a", "x"},
+		Labels:  map[string]string{"team": "core", "tier": "gold"},
+		Notes:   []string{},
+		Count:   2,
+		Secret:  "s2",
+	}
+
+	patch, err := CreatePatch(&before, &after, WithTagName("json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `[{"op":"replace","path":"/name","value":"Alicia"},` +
+		`{"op":"replace","path":"/email","value":null},` +
+		`{"op":"replace","path":"/address/city","value":"Rome"},` +
+		`{"op":"replace","path":"/tags/1","value":"x"},` +
+		`{"op":"remove","path":"/tags/3"},` +
+		`{"op":"remove","path":"/tags/2"},` +
+		`{"op":"remove","path":"/labels/a~1b"},` +
+		`{"op":"add","path":"/labels/tier","value":"gold"},` +
+		`{"op":"replace","path":"/notes","value":[]},` +
+		`{"op":"replace","path":"/Count","value":2}]`
+	if got := marshalPatch(t, patch); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCreatePatch_Inverse(t *testing.T) {
+	email := "alice@example.com"
+	before := patchDoc{
+		Email:  &email,
+		Tags:   []string{"a", "b", "c", "d"},
+		Labels: map[string]string{"team": "core", "a/b": "x"},
+	}
+	after := patchDoc{
+		Tags:   []string{"a", "b", "c", "d", "e"},
+		Labels: map[string]string{"a/b": "x"},
+	}
+
+	patch, err := CreatePatch(before, after, WithTagName("json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `[{"op":"add","path":"/labels/team","value":"core"},` +
+		`{"op":"remove","path":"/tags/4"},` +
+		`{"op":"replace","path":"/email","value":"alice@example.com"}]`
+	if got := marshalPatch(t, patch.Inverse()); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	if got := marshalPatch(t, patch.Inverse().Inverse()); got != marshalPatch(t, patch) {
+		t.Errorf("expected double inverse to equal the patch, got %s", got)
+	}
+}
+
+func TestCreatePatch_CrossType(t *testing.T) {
+	type DocDTO struct {
+		Name  string `json:"name"`
+		Count string `json:"Count" mapconv:"int"`
+	}
+
+	before := patchDoc{Name: "Alice", Count: 1}
+	patch, err := CreatePatch(before, DocDTO{Name: "Alice", Count: "5"}, WithTagName("json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := marshalPatch(t, patch); got != `[{"op":"replace","path":"/Count","value":5}]` {
+		t.Errorf("unexpected patch %s", got)
+	}
+}
+
+func TestCreatePatch_FieldNames(t *testing.T) {
+	before := patchDoc{Name: "Alice", Tags: []string{"a"}, Count: 1}
+	after := patchDoc{Name: "Bob", Tags: []string{"a"}, Count: 1}
+
+	patch, err := New().CreatePatch(before, after)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := marshalPatch(t, patch); got != `[{"op":"replace","path":"/Name","value":"Bob"}]` {
+		t.Errorf("expected Go field names without a tag name, got %s", got)
+	}
+}
+
+func TestCreatePatch_Errors(t *testing.T) {
+	type BadDTO struct {
+		Count string `mapconv:"int"`
+	}
+
+	patch, err := CreatePatch(patchDoc{Name: "Alice", Count: 1}, BadDTO{Count: "x"})
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.FieldPath != "Count" {
+		t.Errorf("expected conversion error at Count, got %v", err)
+	}
+	if patch != nil {
+		t.Errorf("expected no patch, got %+v", patch)
+	}
+
+	if _, err := CreatePatch(patchDoc{Name: "Alice"}, nil); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected nil input, got %v", err)
+	}
+}
@@ -36,12 +36,13 @@ func formatMapKey(key reflect.Value) string {
 // prependMapKeyPath prepends the map key path to a MappingError's FieldPath.
 // This is called only when an error occurs, making path building lazy.
 func prependMapKeyPath(err error, basePath string, key reflect.Value) error {
-	if me, ok := err.(*MappingError); ok {
+	switch e := err.(type) {
+	case *MappingError:
+		e.FieldPath = joinPath(buildMapPath(basePath, key), e.FieldPath)
+	case MappingErrors:
 		keyPath := buildMapPath(basePath, key)
-		if me.FieldPath != "" {
-			me.FieldPath = keyPath + "." + me.FieldPath
-		} else {
-			me.FieldPath = keyPath
+		for _, me := range e {
+			me.FieldPath = joinPath(keyPath, me.FieldPath)
 		}
 	}
 	return err
@@ -53,19 +54,31 @@ func prependMapKeyPath(err error, basePath string, key reflect.Value) error {
 // - empty maps remain empty (not nil)
 // - a new underlying map is created (modifications to source don't affect destination)
 // - key and value types are converted if compatible
-// - nested structs within maps are properly mapped using the provided tagName
-func assignMap(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath, tagName string, depth int) error {
+// - nested structs within maps are properly mapped using the configured tag name
+func assignMap(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	return assignMapMerge(dst, src, mergeSpec{}, srcStructType, dstStructType, fieldPath, cfg, depth)
+}
+
+// assignMapMerge is assignMap with a merge strategy for populated destinations.
+// Every strategy other than MergeReplace keeps the existing entries: MergeUnion
+// only adds keys the destination lacks, the others overwrite matching keys,
+// and prune removes entries whose key is not in the source. A nil source
+// leaves the destination unchanged unless the strategy is MergeReplace.
+func assignMapMerge(dst, src reflect.Value, spec mergeSpec, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
 	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
 	if src.IsNil() {
-		dst.Set(reflect.Zero(dst.Type()))
+		if spec.strategy == MergeReplace {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
 		return nil
 	}
 
@@ -85,9 +98,15 @@ func assignMap(dst, src reflect.Value, srcStructType, dstStructType reflect.Type
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "map key types are incompatible: " + srcKeyType.String() + " -> " + dstKeyType.String(),
+			Code:      CodeIncompatibleTypes,
 		}
 	}
 
+	var conv converterFunc
+	if cfg.hasConverters() {
+		conv, _ = cfg.lookupConverter(srcValType, dstValType)
+	}
+
 	srcValKind := srcValType.Kind()
 	dstValKind := dstValType.Kind()
 
@@ -97,22 +116,69 @@ func assignMap(dst, src reflect.Value, srcStructType, dstStructType reflect.Type
 	valuesArePtrs := srcValKind == reflect.Ptr && dstValKind == reflect.Ptr
 	valuesAssignable := srcValType.AssignableTo(dstValType)
 	valuesConvertible := srcValType.ConvertibleTo(dstValType)
+	// Self-mapped and interface values are assigned one by one, as their
+	// dynamic types decide how
+	valuesNested := conv == nil && (selfMapping(srcValType, dstValType) || srcValKind == reflect.Interface || dstValKind == reflect.Interface)
 
-	if !valuesAssignable && !valuesConvertible && !valuesAreStructs && !valuesAreNestedMaps && !valuesAreNestedSlices && !valuesArePtrs {
+	if conv == nil && !valuesNested && !valuesAssignable && !valuesConvertible && !valuesAreStructs && !valuesAreNestedMaps && !valuesAreNestedSlices && !valuesArePtrs {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "map value types are incompatible: " + srcValType.String() + " -> " + dstValType.String(),
+			Code:      CodeIncompatibleTypes,
+		}
+	}
+
+	// Resolve the value plan once instead of once per entry
+	var valPlan *structPlan
+	if conv == nil && valuesAreStructs && !valuesNested {
+		var err error
+		valPlan, err = cfg.mapper.getStructPlan(srcValType, dstValType, cfg.tagName)
+		if err != nil {
+			return &MappingError{
+				SrcType:   srcStructType.String(),
+				DstType:   dstStructType.String(),
+				FieldPath: fieldPath,
+				Reason:    "failed to get struct metadata: " + err.Error(),
+				Code:      CodeInvalidInput,
+				Err:       err,
+			}
+		}
+	}
+
+	// Patch semantics and MergeByKey merge values into the existing entries,
+	// keeping entries the source does not mention
+	mergeValues := conv == nil && !dst.IsNil() && (spec.strategy == MergeByKey ||
+		cfg.ignoreZeroSource && (valuesAreStructs || (valuesArePtrs && dstValType.Elem().Kind() == reflect.Struct)))
+	keepEntries := !dst.IsNil() && (mergeValues || spec.strategy != MergeReplace)
+
+	var newMap reflect.Value
+	if keepEntries {
+		newMap = reflect.MakeMapWithSize(dType, dst.Len()+src.Len())
+		existing := dst.MapRange()
+		for existing.Next() {
+			newMap.SetMapIndex(existing.Key(), existing.Value())
 		}
+	} else {
+		newMap = reflect.MakeMapWithSize(dType, src.Len())
 	}
 
-	newMap := reflect.MakeMapWithSize(dType, src.Len())
+	// seen records the source keys when entries missing from the source are pruned
+	var seen map[any]struct{}
+	if keepEntries && spec.prune && spec.strategy == MergeByKey {
+		seen = make(map[any]struct{}, src.Len())
+	}
 
-	needsProcessing := valuesAreStructs || valuesAreNestedMaps || valuesAreNestedSlices || valuesArePtrs || (!valuesAssignable && valuesConvertible)
+	needsProcessing := valuesNested || valuesAreStructs || valuesAreNestedMaps || valuesAreNestedSlices || valuesArePtrs || (!valuesAssignable && valuesConvertible)
 
+	var errs MappingErrors
 	iter := src.MapRange()
-	for iter.Next() {
+	for i := 0; iter.Next(); i++ {
+		if err := cfg.checkContext(i, srcStructType, dstStructType, fieldPath); err != nil {
+			return cfg.recordError(&errs, err)
+		}
+
 		srcKey := iter.Key()
 		srcVal := iter.Value()
 
@@ -123,46 +189,88 @@ func assignMap(dst, src reflect.Value, srcStructType, dstStructType reflect.Type
 			dstKey = srcKey.Convert(dstKeyType)
 		}
 
+		if seen != nil {
+			seen[dstKey.Interface()] = struct{}{}
+		}
+		if keepEntries && spec.strategy == MergeUnion && dst.MapIndex(dstKey).IsValid() {
+			continue
+		}
+
 		var dstVal reflect.Value
 		var err error
 
-		if !needsProcessing && valuesAssignable {
+		if mergeValues {
+			if cfg.ignoreZeroSource && srcVal.IsZero() {
+				continue
+			}
+			if existing := newMap.MapIndex(dstKey); existing.IsValid() {
+				dstVal = reflect.New(dstValType).Elem()
+				dstVal.Set(existing)
+				if err = mergeElement(dstVal, srcVal, srcStructType, dstStructType, cfg, depth); err != nil {
+					if err = cfg.recordError(&errs, prependMapKeyPath(err, fieldPath, srcKey)); err != nil {
+						return err
+					}
+					continue
+				}
+				newMap.SetMapIndex(dstKey, dstVal)
+				continue
+			}
+		}
+
+		if conv != nil {
+			dstVal = reflect.New(dstValType).Elem()
+			err = applyConverter(conv, dstVal, srcVal, srcStructType, dstStructType, "", cfg)
+		} else if !needsProcessing && valuesAssignable {
 			dstVal = srcVal
+		} else if valuesNested {
+			dstVal = cfg.newValue(dstValType)
+			// Pass empty path; path is built only on error (lazy)
+			err = assignNestedValue(dstVal, srcVal, srcStructType, dstStructType, "", "", "", cfg, depth-1)
 		} else if valuesAreStructs {
-			dstVal = reflect.New(dstValType).Elem()
+			dstVal = cfg.newValue(dstValType)
 			// Pass empty path; path is built only on error (lazy)
-			err = assignStruct(dstVal, srcVal, srcStructType, dstStructType, "", tagName, depth-1)
-			if err != nil {
-				return prependMapKeyPath(err, fieldPath, srcKey)
-			}
+			err = assignStructPlan(dstVal, srcVal, valPlan, srcStructType, dstStructType, "", cfg, depth-1)
 		} else if valuesAreNestedMaps {
 			dstVal = reflect.New(dstValType).Elem()
 			// Pass empty path; path is built only on error (lazy)
-			err = assignMap(dstVal, srcVal, srcStructType, dstStructType, "", tagName, depth-1)
-			if err != nil {
-				return prependMapKeyPath(err, fieldPath, srcKey)
-			}
+			err = assignMap(dstVal, srcVal, srcStructType, dstStructType, "", cfg, depth-1)
 		} else if valuesAreNestedSlices {
 			dstVal = reflect.New(dstValType).Elem()
 			// Pass empty path; path is built only on error (lazy)
-			err = assignSlice(dstVal, srcVal, srcStructType, dstStructType, "", tagName, depth-1)
-			if err != nil {
-				return prependMapKeyPath(err, fieldPath, srcKey)
-			}
+			err = assignSlice(dstVal, srcVal, srcStructType, dstStructType, "", cfg, depth-1)
 		} else if valuesArePtrs {
 			dstVal = reflect.New(dstValType).Elem()
-			// Pass empty path; path is built only on error (lazy)
-			err = assignPointerElement(dstVal, srcVal, srcStructType, dstStructType, "", tagName, depth-1)
-			if err != nil {
-				return prependMapKeyPath(err, fieldPath, srcKey)
+			if cfg.pointers == pointerReuse && !dst.IsNil() {
+				// Map into the pointee stored under the same key
+				if existing := dst.MapIndex(dstKey); existing.IsValid() {
+					dstVal.Set(existing)
+				}
 			}
+			// Pass empty path; path is built only on error (lazy)
+			err = assignPointerElement(dstVal, srcVal, srcStructType, dstStructType, "", cfg, depth-1)
 		} else if valuesConvertible {
 			dstVal = srcVal.Convert(dstValType)
 		}
 
+		if err != nil {
+			if err = cfg.recordError(&errs, prependMapKeyPath(err, fieldPath, srcKey)); err != nil {
+				return err
+			}
+			continue
+		}
+
 		newMap.SetMapIndex(dstKey, dstVal)
 	}
 
+	if seen != nil {
+		keys := newMap.MapRange()
+		for keys.Next() {
+			if _, ok := seen[keys.Key().Interface()]; !ok {
+				newMap.SetMapIndex(keys.Key(), reflect.Value{})
+			}
+		}
+	}
+
 	dst.Set(newMap)
-	return nil
+	return errs.orNil()
 }
@@ -8,6 +8,9 @@ package mapper
 //
 // Map performs deep copying for slices, maps, and nested structs. Pointer
 // fields are handled flexibly: values can map to pointers and vice versa.
+// Interface source fields, such as any, are mapped from the value they hold;
+// a nil interface sets the destination field to its zero value, whatever its
+// kind.
 //
 // Only exported fields are mapped. Unexported fields are silently ignored.
 //
@@ -34,7 +37,7 @@ package mapper
 // Returns a [*MappingError] if mapping fails. Common failure causes include
 // type incompatibility, nil pointers, and string conversion errors.
 func Map(dst any, src any) error {
-	return MapWithOptions(dst, src)
+	return defaultMapper.MapWithOptions(dst, src)
 }
 
 // MapWithOptions copies fields from src to dst with custom configuration.
@@ -62,9 +65,5 @@ func Map(dst any, src any) error {
 //
 // Returns a [*MappingError] if mapping fails.
 func MapWithOptions(dst any, src any, opts ...Option) error {
-	cfg := defaultConfig()
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-	return runMapping(dst, src, &cfg)
+	return defaultMapper.MapWithOptions(dst, src, opts...)
 }
@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -266,6 +267,121 @@ func TestMapWithOptions_WithIgnoreZeroSource(t *testing.T) {
 	}
 }
 
+type patchAddress struct {
+	Street string
+	City   string
+}
+
+type patchUser struct {
+	Name     string
+	Address  patchAddress
+	Billing  *patchAddress
+	Shipping map[string]patchAddress
+	Offices  map[string]*patchAddress
+}
+
+func TestMapWithOptions_WithIgnoreZeroSource_Nested(t *testing.T) {
+	billing := &patchAddress{Street: "1 Bill St", City: "Paris"}
+	dst := patchUser{
+		Name:    "Alice",
+		Address: patchAddress{Street: "1 Main St", City: "Lyon"},
+		Billing: billing,
+	}
+
+	patch := patchUser{
+		Address: patchAddress{City: "Nice"},
+		Billing: &patchAddress{City: "Rome"},
+	}
+
+	if err := MapWithOptions(&dst, patch, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "Alice" {
+		t.Errorf("expected Name to be preserved, got %q", dst.Name)
+	}
+	if dst.Address != (patchAddress{Street: "1 Main St", City: "Nice"}) {
+		t.Errorf("expected nested struct to be merged, got %+v", dst.Address)
+	}
+	if dst.Billing != billing {
+		t.Error("expected the existing pointee to be reused")
+	}
+	if *dst.Billing != (patchAddress{Street: "1 Bill St", City: "Rome"}) {
+		t.Errorf("expected pointee to be merged, got %+v", *dst.Billing)
+	}
+	if patch.Billing.Street != "" {
+		t.Error("expected source to be unchanged")
+	}
+}
+
+func TestMapWithOptions_WithIgnoreZeroSource_NilPointee(t *testing.T) {
+	var dst patchUser
+	patch := patchUser{Billing: &patchAddress{City: "Rome"}}
+
+	if err := MapWithOptions(&dst, patch, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Billing == nil || dst.Billing == patch.Billing || dst.Billing.City != "Rome" {
+		t.Errorf("expected a new pointee to be allocated, got %+v", dst.Billing)
+	}
+}
+
+func TestMapWithOptions_WithIgnoreZeroSource_MapValues(t *testing.T) {
+	office := &patchAddress{Street: "5 Office Rd", City: "Berlin"}
+	dst := patchUser{
+		Shipping: map[string]patchAddress{
+			"home": {Street: "1 Main St", City: "Lyon"},
+			"work": {Street: "2 Work Ave", City: "Lyon"},
+		},
+		Offices: map[string]*patchAddress{"hq": office},
+	}
+	original := dst.Shipping
+
+	patch := patchUser{
+		Shipping: map[string]patchAddress{
+			"home":    {City: "Nice"},
+			"holiday": {Street: "3 Beach Rd"},
+		},
+		Offices: map[string]*patchAddress{"hq": {City: "Munich"}},
+	}
+
+	if err := MapWithOptions(&dst, patch, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]patchAddress{
+		"home":    {Street: "1 Main St", City: "Nice"},
+		"work":    {Street: "2 Work Ave", City: "Lyon"},
+		"holiday": {Street: "3 Beach Rd"},
+	}
+	if len(dst.Shipping) != len(want) {
+		t.Fatalf("expected %d entries, got %v", len(want), dst.Shipping)
+	}
+	for k, v := range want {
+		if dst.Shipping[k] != v {
+			t.Errorf("Shipping[%s]: expected %+v, got %+v", k, v, dst.Shipping[k])
+		}
+	}
+	if original["home"].City != "Lyon" {
+		t.Error("expected the original destination map not to be modified")
+	}
+
+	if dst.Offices["hq"] != office || office.Street != "5 Office Rd" || office.City != "Munich" {
+		t.Errorf("expected pointer map value to be merged in place, got %+v", dst.Offices["hq"])
+	}
+}
+
+func TestMapWithOptions_WithIgnoreZeroSource_IdenticalTypes(t *testing.T) {
+	dst := patchAddress{Street: "1 Main St", City: "Lyon"}
+
+	if err := MapWithOptions(&dst, patchAddress{City: "Nice"}, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Street != "1 Main St" || dst.City != "Nice" {
+		t.Errorf("expected merge for identical types, got %+v", dst)
+	}
+}
+
 func TestMapWithOptions_WithStrictMode(t *testing.T) {
 	type Src struct {
 		Name string
@@ -295,6 +411,422 @@ func TestMapWithOptions_WithStrictMode(t *testing.T) {
 	}
 }
 
+type strictAddrSrc struct {
+	Street string
+}
+
+type strictAddrDst struct {
+	Street string
+	Zip    string
+}
+
+type strictItemSrc struct {
+	Name string
+}
+
+type strictItemDst struct {
+	Name string
+	Sku  string
+}
+
+type strictOrderSrc struct {
+	ID      int
+	Address strictAddrSrc
+	Items   []strictItemSrc
+	ByCode  map[string]strictItemSrc
+}
+
+type strictOrderDst struct {
+	ID      int
+	Address strictAddrDst
+	Items   []strictItemDst
+	ByCode  map[string]strictItemDst
+}
+
+func TestMapWithOptions_WithStrictMode_Nested(t *testing.T) {
+	var dst strictOrderDst
+	err := MapWithOptions(&dst, strictOrderSrc{ID: 1}, WithStrictMode())
+
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T (%v)", err, err)
+	}
+	if mappingErr.FieldPath != "Address.Zip" {
+		t.Errorf("expected FieldPath 'Address.Zip', got %q", mappingErr.FieldPath)
+	}
+	if mappingErr.Code != CodeUnmatchedField {
+		t.Errorf("expected CodeUnmatchedField, got %q", mappingErr.Code)
+	}
+}
+
+func TestMapWithOptions_WithStrictMode_CollectsNestedPaths(t *testing.T) {
+	src := strictOrderSrc{
+		Items:  []strictItemSrc{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		ByCode: map[string]strictItemSrc{"x": {Name: "x"}},
+	}
+
+	var dst strictOrderDst
+	err := MapWithOptions(&dst, src, WithStrictMode(), WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MappingErrors, got %T (%v)", err, err)
+	}
+
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.FieldPath)
+	}
+	want := "Address.Zip,Items[0].Sku,Items[1].Sku,Items[2].Sku,ByCode[x].Sku"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("expected paths %s, got %s", want, got)
+	}
+}
+
+func TestMapWithOptions_WithStrictTypes(t *testing.T) {
+	src := strictOrderSrc{Items: []strictItemSrc{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+
+	var dst strictOrderDst
+	err := MapWithOptions(&dst, src, WithStrictTypes(&strictItemDst{}))
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T (%v)", err, err)
+	}
+	if mappingErr.FieldPath != "Items[0].Sku" {
+		t.Errorf("expected only strict types to be checked, got %q", mappingErr.FieldPath)
+	}
+
+	// Address is not a strict type, so its unmatched Zip is ignored
+	dst = strictOrderDst{}
+	if err := MapWithOptions(&dst, strictOrderSrc{ID: 1}, WithStrictTypes(strictItemDst{})); err != nil {
+		t.Errorf("expected no error for lenient types, got %v", err)
+	}
+
+	// Only the listed type is strict, not the structs around it
+	err = MapWithOptions(&dst, strictOrderSrc{}, WithStrictTypes(strictAddrDst{}))
+	if mappingErr, ok := err.(*MappingError); !ok || mappingErr.FieldPath != "Address.Zip" {
+		t.Errorf("expected Address.Zip error, got %v", err)
+	}
+}
+
+type strictSourceAddr struct {
+	Street  string
+	Country string
+}
+
+type strictSourceAddrDTO struct {
+	Street string
+}
+
+type strictSourceUser struct {
+	Name         string
+	Email        string `map:"ContactEmail"`
+	PasswordHash string `mapstrict:"-"`
+	Nickname     string
+	Address      strictSourceAddr
+	Previous     []strictSourceAddr
+}
+
+type strictSourceUserDTO struct {
+	Name         string
+	ContactEmail string
+	Address      strictSourceAddrDTO
+	Previous     []strictSourceAddrDTO
+}
+
+func TestMapWithOptions_WithStrictSource(t *testing.T) {
+	src := strictSourceUser{
+		Name:     "Alice",
+		Previous: []strictSourceAddr{{Street: "a"}, {Street: "b"}},
+	}
+
+	var dst strictSourceUserDTO
+	err := MapWithOptions(&dst, src, WithStrictSource())
+
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T (%v)", err, err)
+	}
+	if mappingErr.FieldPath != "Address.Country" {
+		t.Errorf("expected FieldPath 'Address.Country', got %q", mappingErr.FieldPath)
+	}
+	if !errors.Is(err, ErrUnmappedSource) {
+		t.Errorf("expected ErrUnmappedSource, got %v", err)
+	}
+}
+
+func TestMapWithOptions_WithStrictSource_CollectsAll(t *testing.T) {
+	src := strictSourceUser{Previous: []strictSourceAddr{{}, {}}}
+
+	var dst strictSourceUserDTO
+	err := MapWithOptions(&dst, src, WithStrictSource(), WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MappingErrors, got %T (%v)", err, err)
+	}
+
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.FieldPath)
+	}
+	// Email is consumed through its tag and PasswordHash opts out
+	want := "Address.Country,Previous[0].Country,Previous[1].Country,Nickname"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("expected paths %s, got %s", want, got)
+	}
+}
+
+func TestMapWithOptions_WithStrictSource_AllConsumed(t *testing.T) {
+	type Src struct {
+		Name   string
+		Secret string `mapstrict:"-"`
+	}
+	type Dst struct {
+		Name  string
+		Extra string
+	}
+
+	var dst Dst
+	if err := MapWithOptions(&dst, Src{Name: "Alice"}, WithStrictSource()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+type reuseProfile struct {
+	Bio     string
+	Visits  *int
+	Private string
+}
+
+type reuseProfileSrc struct {
+	Bio    string
+	Visits *int
+}
+
+func TestMapWithOptions_WithReusePointers(t *testing.T) {
+	type Src struct {
+		Profile  *reuseProfileSrc
+		Count    *int
+		Friends  []*reuseProfileSrc
+		ByHandle map[string]*reuseProfileSrc
+	}
+	type Dst struct {
+		Profile  *reuseProfile
+		Count    *int
+		Friends  []*reuseProfile
+		ByHandle map[string]*reuseProfile
+	}
+
+	visits, count := 7, 3
+	profile := &reuseProfile{Bio: "old", Private: "keep"}
+	countPtr := new(int)
+	friend := &reuseProfile{Private: "friend"}
+	handle := &reuseProfile{Private: "handle"}
+	dst := Dst{
+		Profile:  profile,
+		Count:    countPtr,
+		Friends:  []*reuseProfile{friend},
+		ByHandle: map[string]*reuseProfile{"a": handle},
+	}
+	src := Src{
+		Profile:  &reuseProfileSrc{Bio: "new", Visits: &visits},
+		Count:    &count,
+		Friends:  []*reuseProfileSrc{{Bio: "f1"}, {Bio: "f2"}},
+		ByHandle: map[string]*reuseProfileSrc{"a": {Bio: "h"}, "b": {Bio: "other"}},
+	}
+
+	if err := MapWithOptions(&dst, src, WithReusePointers()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Profile != profile || profile.Bio != "new" || profile.Private != "keep" || *profile.Visits != 7 {
+		t.Errorf("expected profile to be updated in place, got %+v", dst.Profile)
+	}
+	if dst.Count != countPtr || *countPtr != 3 {
+		t.Errorf("expected count pointee to be reused, got %v", dst.Count)
+	}
+	if len(dst.Friends) != 2 || dst.Friends[0] != friend || friend.Bio != "f1" || friend.Private != "friend" || dst.Friends[1].Bio != "f2" {
+		t.Errorf("expected slice element pointee to be reused, got %+v", dst.Friends)
+	}
+	if dst.ByHandle["a"] != handle || handle.Bio != "h" || handle.Private != "handle" || dst.ByHandle["b"].Bio != "other" {
+		t.Errorf("expected map value pointee to be reused, got %+v", dst.ByHandle)
+	}
+}
+
+func TestMapWithOptions_WithReusePointers_NilSource(t *testing.T) {
+	type Src struct{ Profile *reuseProfileSrc }
+	type Dst struct{ Profile *reuseProfile }
+
+	dst := Dst{Profile: &reuseProfile{Bio: "old"}}
+	if err := MapWithOptions(&dst, Src{}, WithReusePointers()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Profile != nil {
+		t.Errorf("expected nil source to clear the pointer, got %+v", dst.Profile)
+	}
+}
+
+func TestMap_PointerTag(t *testing.T) {
+	type Src struct {
+		Reused  *reuseProfileSrc
+		Default *reuseProfileSrc
+	}
+	type Dst struct {
+		Reused  *reuseProfile `mapptr:"reuse"`
+		Default *reuseProfile
+	}
+
+	reused := &reuseProfile{Private: "keep"}
+	other := &reuseProfile{Private: "drop"}
+	dst := Dst{Reused: reused, Default: other}
+
+	if err := Map(&dst, Src{Reused: &reuseProfileSrc{Bio: "a"}, Default: &reuseProfileSrc{Bio: "b"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Reused != reused || reused.Bio != "a" || reused.Private != "keep" {
+		t.Errorf("expected tagged field to reuse its pointee, got %+v", dst.Reused)
+	}
+	if dst.Default == other || dst.Default.Private != "" {
+		t.Errorf("expected untagged field to be reallocated, got %+v", dst.Default)
+	}
+
+	// mapptr:"new" opts out of the option, including under patch semantics
+	type NewDst struct {
+		Profile *reuseProfile `mapptr:"new"`
+	}
+	kept := &reuseProfile{Private: "old"}
+	newDst := NewDst{Profile: kept}
+	type ProfileSrc struct{ Profile *reuseProfileSrc }
+	if err := MapWithOptions(&newDst, ProfileSrc{Profile: &reuseProfileSrc{Bio: "x"}}, WithReusePointers(), WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newDst.Profile == kept || kept.Bio != "" {
+		t.Errorf("expected mapptr:\"new\" to allocate, got %+v", newDst.Profile)
+	}
+
+	type BadDst struct {
+		Profile *reuseProfile `mapptr:"share"`
+	}
+	var bad BadDst
+	if err := Map(&bad, ProfileSrc{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid mapptr tag error, got %v", err)
+	}
+}
+
+type refNode struct {
+	Name     string
+	Parent   *refNode
+	Children []*refNode
+}
+
+type refNodeDTO struct {
+	Name     string
+	Parent   *refNodeDTO
+	Children []*refNodeDTO
+}
+
+type refListNode struct {
+	Value int
+	Prev  *refListNode
+	Next  *refListNode
+}
+
+func TestMapWithOptions_WithPreserveReferences_Tree(t *testing.T) {
+	root := &refNode{Name: "root"}
+	a := &refNode{Name: "a", Parent: root}
+	b := &refNode{Name: "b", Parent: root}
+	root.Children = []*refNode{a, b}
+
+	var dst refNodeDTO
+	if err := MapWithOptions(&dst, root, WithPreserveReferences()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dst.Children) != 2 || dst.Children[0].Name != "a" || dst.Children[1].Name != "b" {
+		t.Fatalf("unexpected children %+v", dst.Children)
+	}
+	for _, child := range dst.Children {
+		if child.Parent != &dst {
+			t.Errorf("expected %s.Parent to point at the destination root", child.Name)
+		}
+	}
+}
+
+func TestMapWithOptions_WithPreserveReferences_DoublyLinked(t *testing.T) {
+	first := &refListNode{Value: 1}
+	second := &refListNode{Value: 2, Prev: first}
+	third := &refListNode{Value: 3, Prev: second}
+	first.Next, second.Next = second, third
+
+	var dst refListNode
+	if err := MapWithOptions(&dst, first, WithPreserveReferences()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Next.Prev != &dst || dst.Next.Next.Prev != dst.Next || dst.Next.Next.Value != 3 {
+		t.Errorf("expected links to be preserved, got %+v", dst)
+	}
+	if dst.Next == second {
+		t.Error("expected nodes to be copied")
+	}
+}
+
+func TestMapWithOptions_WithPreserveReferences_SharedPointers(t *testing.T) {
+	type Address struct{ City string }
+	type Src struct {
+		Billing  *Address
+		Shipping *Address
+		History  []*Address
+		ByName   map[string]*Address
+		Score    *int
+		Rank     *int
+	}
+
+	addr := &Address{City: "Paris"}
+	score := 7
+	src := Src{Billing: addr, Shipping: addr, History: []*Address{addr}, ByName: map[string]*Address{"home": addr}, Score: &score, Rank: &score}
+
+	var dst Src
+	if err := MapWithOptions(&dst, src, WithPreserveReferences()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Billing == addr || dst.Billing != dst.Shipping || dst.History[0] != dst.Billing || dst.ByName["home"] != dst.Billing {
+		t.Errorf("expected one shared copy of the address, got %p %p %p %p", dst.Billing, dst.Shipping, dst.History[0], dst.ByName["home"])
+	}
+	if dst.Score == &score || dst.Score != dst.Rank {
+		t.Error("expected one shared copy of the score")
+	}
+
+	// Without the option every reference gets its own copy
+	var copied Src
+	if err := Map(&copied, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied.Billing == copied.Shipping {
+		t.Error("expected separate copies without WithPreserveReferences")
+	}
+}
+
+func TestMapWithOptions_WithPreserveReferences_CycleWithoutOption(t *testing.T) {
+	node := &refListNode{Value: 1}
+	node.Next = node
+
+	var dst refListNode
+	err := MapWithOptions(&dst, node, WithMaxDepth(16))
+	if !errors.Is(err, ErrDepthExceeded) {
+		t.Fatalf("expected depth error without WithPreserveReferences, got %v", err)
+	}
+
+	dst = refListNode{}
+	if err := New(WithPreserveReferences()).Map(&dst, node); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Next != &dst {
+		t.Error("expected the self-reference to point at the destination")
+	}
+}
+
 func TestMapWithOptions_MultipleOptions(t *testing.T) {
 	type Src struct {
 		UserName string `custom:"Name"`
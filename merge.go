@@ -0,0 +1,412 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy selects how a slice or map field is combined with the value
+// the destination already holds.
+type MergeStrategy uint8
+
+const (
+	// MergeReplace replaces the destination collection with a mapped copy of
+	// the source. This is the default.
+	MergeReplace MergeStrategy = iota
+	// MergeAppend appends the mapped source elements to a slice. For maps,
+	// source entries are added and overwrite entries with the same key.
+	MergeAppend
+	// MergeUnion appends only the source elements that are not already in the
+	// slice, compared with reflect.DeepEqual. For maps, only keys missing from
+	// the destination are added.
+	MergeUnion
+	// MergeByKey merges source elements into the matching destination
+	// elements. Slices match elements by the field named with the mapkey tag,
+	// or by index without one; maps match entries by key. Matching elements
+	// are updated in place and new ones are added.
+	MergeByKey
+)
+
+// mergeSpec is a resolved merge strategy for one collection field.
+type mergeSpec struct {
+	strategy MergeStrategy
+	key      string // element field matched by MergeByKey on slices
+	prune    bool   // remove destination elements missing from the source
+}
+
+var mergeStrategies = map[string]MergeStrategy{
+	"replace": MergeReplace,
+	"append":  MergeAppend,
+	"union":   MergeUnion,
+	"merge":   MergeByKey,
+}
+
+// parseMergeTags parses the mapmerge and mapkey tags of a destination field
+// of type t. It returns a non-empty reason when the tags are invalid.
+func parseMergeTags(mergeTag, keyTag string, hasKey bool, t reflect.Type) (mergeSpec, string) {
+	var spec mergeSpec
+
+	t = indirectType(t)
+	if kind := t.Kind(); kind != reflect.Slice && kind != reflect.Map {
+		return spec, "mapmerge and mapkey tags require a slice or map field, or a pointer to one"
+	}
+
+	name, option, _ := strings.Cut(mergeTag, ",")
+	switch {
+	case name == "" && hasKey:
+		spec.strategy = MergeByKey
+	case name != "":
+		strategy, ok := mergeStrategies[name]
+		if !ok {
+			return spec, "unknown mapmerge strategy: " + name
+		}
+		spec.strategy = strategy
+	}
+
+	switch option {
+	case "":
+	case "prune":
+		spec.prune = true
+	default:
+		return spec, "unknown mapmerge option: " + option
+	}
+
+	if hasKey {
+		if keyTag == "" || t.Kind() != reflect.Slice || spec.strategy != MergeByKey {
+			return spec, "mapkey requires a field name on a slice merged with mapmerge:\"merge\""
+		}
+		spec.key = keyTag
+	}
+
+	return spec, ""
+}
+
+// mergeFor returns the merge strategy for the collection field fp and
+// reports whether it differs from MergeReplace. Field tags take precedence
+// over WithMergeStrategy; WithPruneMissing applies to every merged field.
+func (c *config) mergeFor(fp *fieldPlan) (mergeSpec, bool) {
+	if !fp.mergeTagged {
+		return c.merge, c.merge.strategy != MergeReplace
+	}
+	spec := fp.merge
+	spec.prune = spec.prune || c.merge.prune
+	return spec, spec.strategy != MergeReplace
+}
+
+// WithMergeStrategy sets how slice and map fields are combined with the
+// values already in the destination. The default is [MergeReplace], which
+// replaces them. Fields can override the strategy with the mapmerge tag,
+// and slices merged with [MergeByKey] name their key field with mapkey:
+//
+//	type Team struct {
+//	    Members []Member          `mapkey:"ID"`            // merge members by ID
+//	    Tags    []string          `mapmerge:"union"`       // add missing tags
+//	    Labels  map[string]string `mapmerge:"merge,prune"` // sync labels
+//	}
+//
+//	err := mapper.MapWithOptions(&team, req, mapper.WithMergeStrategy(mapper.MergeAppend))
+//
+// Merging never modifies the backing array or map of the destination: the
+// field is set to a new collection holding the merged elements, except that
+// existing pointees of pointer elements are updated in place. Fields holding
+// a pointer to a slice or map, and source pointers and optionals, are merged
+// the same way; a destination pointer is handled like other pointers, so its
+// pointee is replaced unless [WithReusePointers] or mapptr:"reuse" applies.
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(c *config) {
+		c.merge.strategy = s
+	}
+}
+
+// WithPruneMissing configures fields merged with [MergeByKey] to remove the
+// destination elements that have no match in the source, so the result
+// mirrors the source while keeping the existing elements' unmapped fields.
+// Individual fields enable pruning with mapmerge:"merge,prune".
+//
+// Example:
+//
+//	err := mapper.MapWithOptions(&team, req,
+//	    mapper.WithMergeStrategy(mapper.MergeByKey),
+//	    mapper.WithPruneMissing(),
+//	)
+func WithPruneMissing() Option {
+	return func(c *config) {
+		c.merge.prune = true
+	}
+}
+
+// mergeValue applies spec to a collection field reached through a source
+// pointer or optional, or held by a destination pointer. A nil source pointer
+// leaves dst unchanged, like a nil collection. The merged collection is
+// stored through the pointer chosen by [newPointee]. It reports false when
+// the values are not slices or maps of the same kind, leaving the
+// assignment to the engine.
+func mergeValue(dst, src reflect.Value, spec mergeSpec, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) (bool, error) {
+	target := dst
+	if dst.Kind() == reflect.Ptr {
+		target = reflect.New(dst.Type().Elem()).Elem()
+		if !dst.IsNil() {
+			target.Set(dst.Elem())
+		}
+	}
+	kind := target.Kind()
+	if kind != reflect.Slice && kind != reflect.Map {
+		return false, nil
+	}
+
+	for src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			return true, nil
+		}
+		src = src.Elem()
+	}
+	if src.Kind() != kind {
+		return false, nil
+	}
+
+	var err error
+	if kind == reflect.Slice {
+		err = mergeSlice(target, src, spec, srcStructType, dstStructType, fieldPath, cfg, depth-1)
+	} else {
+		err = assignMapMerge(target, src, spec, srcStructType, dstStructType, fieldPath, cfg, depth-1)
+	}
+	if err != nil || dst.Kind() != reflect.Ptr {
+		return true, err
+	}
+
+	ptr := newPointee(dst, cfg)
+	ptr.Elem().Set(target)
+	dst.Set(ptr)
+	return true, nil
+}
+
+// mergeSlice maps src into the populated slice dst according to spec. A nil
+// src leaves dst unchanged.
+func mergeSlice(dst, src reflect.Value, spec mergeSpec, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	if depth <= 0 {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
+		}
+	}
+
+	if src.IsNil() {
+		return nil
+	}
+
+	switch spec.strategy {
+	case MergeAppend, MergeUnion:
+		mapped := reflect.New(dst.Type()).Elem()
+		if err := assignSlice(mapped, src, srcStructType, dstStructType, fieldPath, cfg, depth); err != nil {
+			return err
+		}
+
+		result := reflect.MakeSlice(dst.Type(), dst.Len(), dst.Len()+mapped.Len())
+		reflect.Copy(result, dst)
+		for i := 0; i < mapped.Len(); i++ {
+			elem := mapped.Index(i)
+			if spec.strategy == MergeUnion && containsValue(result, elem) {
+				continue
+			}
+			result = reflect.Append(result, elem)
+		}
+		dst.Set(result)
+		return nil
+
+	case MergeByKey:
+		if spec.key != "" {
+			return mergeSliceByKey(dst, src, spec, srcStructType, dstStructType, fieldPath, cfg, depth)
+		}
+		return mergeSliceByIndex(dst, src, spec, srcStructType, dstStructType, fieldPath, cfg, depth)
+
+	default:
+		return assignSlice(dst, src, srcStructType, dstStructType, fieldPath, cfg, depth)
+	}
+}
+
+// mergeSliceByIndex merges src[i] into dst[i] and appends the remaining
+// source elements. With prune, dst is truncated to the length of src.
+func mergeSliceByIndex(dst, src reflect.Value, spec mergeSpec, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	length := max(dst.Len(), src.Len())
+	if spec.prune {
+		length = src.Len()
+	}
+
+	result := reflect.MakeSlice(dst.Type(), length, length)
+	reflect.Copy(result, dst)
+
+	var errs MappingErrors
+	for i := 0; i < src.Len(); i++ {
+		if err := cfg.checkContext(i, srcStructType, dstStructType, fieldPath); err != nil {
+			return cfg.recordError(&errs, err)
+		}
+		if err := mergeElement(result.Index(i), src.Index(i), srcStructType, dstStructType, cfg, depth); err != nil {
+			if err = cfg.recordError(&errs, prependIndexPath(err, fieldPath, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	dst.Set(result)
+	return errs.orNil()
+}
+
+// mergeSliceByKey merges every source element into the destination element
+// with the same value in the spec.key field and appends the unmatched ones.
+// With prune, destination elements no source element matched are removed.
+func mergeSliceByKey(dst, src reflect.Value, spec mergeSpec, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	invalid := func(reason string) error {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    reason,
+			Code:      CodeInvalidMerge,
+		}
+	}
+
+	srcElemType := indirectType(src.Type().Elem())
+	dstElemType := indirectType(dst.Type().Elem())
+	if srcElemType.Kind() != reflect.Struct || dstElemType.Kind() != reflect.Struct {
+		return invalid("mapkey requires slices of structs or struct pointers")
+	}
+
+	plan, err := cfg.mapper.getStructPlan(srcElemType, dstElemType, cfg.tagName)
+	if err != nil {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    "failed to get struct metadata: " + err.Error(),
+			Code:      CodeInvalidInput,
+			Err:       err,
+		}
+	}
+
+	var keyField *fieldPlan
+	for i := range plan.fields {
+		if fp := &plan.fields[i]; fp.name == spec.key && !fp.unmatched {
+			keyField = fp
+			break
+		}
+	}
+	if keyField == nil {
+		return invalid("merge key field " + spec.key + " is not mapped between the element types")
+	}
+	if (keyField.strategy != strategySet && keyField.strategy != strategyConvert) || !keyField.dstType.Comparable() {
+		return invalid("merge key field " + spec.key + " must be a comparable value")
+	}
+
+	result := reflect.MakeSlice(dst.Type(), dst.Len(), dst.Len()+src.Len())
+	reflect.Copy(result, dst)
+
+	positions := make(map[any]int, dst.Len())
+	for i := 0; i < result.Len(); i++ {
+		if key, ok := elementKey(result.Index(i), keyField.dstIndex, keyField.dstType); ok {
+			if _, dup := positions[key]; !dup {
+				positions[key] = i
+			}
+		}
+	}
+
+	var matched []bool
+	if spec.prune {
+		matched = make([]bool, dst.Len())
+	}
+
+	var errs MappingErrors
+	for i := 0; i < src.Len(); i++ {
+		if err := cfg.checkContext(i, srcStructType, dstStructType, fieldPath); err != nil {
+			return cfg.recordError(&errs, err)
+		}
+
+		srcElem := src.Index(i)
+		key, hasKey := elementKey(srcElem, keyField.srcIndex, keyField.dstType)
+
+		pos, found := positions[key]
+		if hasKey && found {
+			if pos < len(matched) {
+				matched[pos] = true
+			}
+			err = mergeElement(result.Index(pos), srcElem, srcStructType, dstStructType, cfg, depth)
+		} else {
+			elem := cfg.newValue(result.Type().Elem())
+			if err = mergeElement(elem, srcElem, srcStructType, dstStructType, cfg, depth); err == nil {
+				result = reflect.Append(result, elem)
+				if hasKey {
+					positions[key] = result.Len() - 1
+				}
+			}
+		}
+
+		if err != nil {
+			if err = cfg.recordError(&errs, prependIndexPath(err, fieldPath, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if matched != nil {
+		kept := reflect.MakeSlice(dst.Type(), 0, result.Len())
+		for i := 0; i < result.Len(); i++ {
+			if i >= len(matched) || matched[i] {
+				kept = reflect.Append(kept, result.Index(i))
+			}
+		}
+		result = kept
+	}
+
+	dst.Set(result)
+	return errs.orNil()
+}
+
+// mergeElement maps src into the existing collection element dst. The pointee
+// of a non-nil pointer element is updated in place.
+func mergeElement(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, cfg *config, depth int) error {
+	if dst.Kind() == reflect.Ptr && !dst.IsNil() {
+		if src.Kind() != reflect.Ptr {
+			return assignNestedValue(dst.Elem(), src, srcStructType, dstStructType, "", "", "", cfg, depth-1)
+		}
+		if !src.IsNil() {
+			return assignNestedValue(dst.Elem(), src.Elem(), srcStructType, dstStructType, "", "", "", cfg, depth-1)
+		}
+	}
+	return assignNestedValue(dst, src, srcStructType, dstStructType, "", "", "", cfg, depth)
+}
+
+// elementKey returns the value of the key field at index of the struct or
+// struct pointer elem, converted to keyType. It reports false for nil pointers.
+func elementKey(elem reflect.Value, index int, keyType reflect.Type) (any, bool) {
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return nil, false
+		}
+		elem = elem.Elem()
+	}
+	key := elem.Field(index)
+	if key.Type() != keyType {
+		key = key.Convert(keyType)
+	}
+	return key.Interface(), true
+}
+
+// containsValue reports whether the slice s has an element deeply equal to v.
+func containsValue(s, v reflect.Value) bool {
+	target := v.Interface()
+	for i := 0; i < s.Len(); i++ {
+		if reflect.DeepEqual(s.Index(i).Interface(), target) {
+			return true
+		}
+	}
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
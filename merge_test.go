@@ -0,0 +1,374 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type mergeMember struct {
+	ID   int
+	Name string
+}
+
+type mergeMemberDst struct {
+	ID    int64
+	Name  string
+	Notes string
+}
+
+type mergeTeamSrc struct {
+	Members []mergeMember
+	Tags    []string
+	Labels  map[string]string
+}
+
+type mergeTeamDst struct {
+	Members []mergeMemberDst `mapkey:"ID"`
+	Tags    []string         `mapmerge:"union"`
+	Labels  map[string]string
+}
+
+func TestMergeStrategy_SliceAppend(t *testing.T) {
+	type Src struct{ Tags []string }
+	dst := struct{ Tags []string }{Tags: []string{"a", "b"}}
+	original := dst.Tags
+
+	err := MapWithOptions(&dst, Src{Tags: []string{"b", "c"}}, WithMergeStrategy(MergeAppend))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"a", "b", "b", "c"}; !reflect.DeepEqual(dst.Tags, want) {
+		t.Errorf("expected %v, got %v", want, dst.Tags)
+	}
+	if !reflect.DeepEqual(original, []string{"a", "b"}) {
+		t.Errorf("expected original backing array to be untouched, got %v", original)
+	}
+}
+
+func TestMergeStrategy_SliceUnion(t *testing.T) {
+	dst := mergeTeamDst{Tags: []string{"a", "b"}}
+
+	if err := Map(&dst, mergeTeamSrc{Tags: []string{"b", "c", "c"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(dst.Tags, want) {
+		t.Errorf("expected %v, got %v", want, dst.Tags)
+	}
+}
+
+func TestMergeStrategy_SliceByKey(t *testing.T) {
+	dst := mergeTeamDst{Members: []mergeMemberDst{
+		{ID: 1, Name: "Alice", Notes: "lead"},
+		{ID: 2, Name: "Bob", Notes: "ops"},
+	}}
+	src := mergeTeamSrc{Members: []mergeMember{{ID: 2, Name: "Robert"}, {ID: 3, Name: "Carol"}}}
+
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []mergeMemberDst{
+		{ID: 1, Name: "Alice", Notes: "lead"},
+		{ID: 2, Name: "Robert", Notes: "ops"},
+		{ID: 3, Name: "Carol"},
+	}
+	if !reflect.DeepEqual(dst.Members, want) {
+		t.Errorf("expected %+v, got %+v", want, dst.Members)
+	}
+}
+
+func TestMergeStrategy_SliceByKeyPrune(t *testing.T) {
+	type Dst struct {
+		Members []mergeMemberDst `mapmerge:"merge,prune" mapkey:"ID"`
+	}
+	dst := Dst{Members: []mergeMemberDst{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob", Notes: "ops"}}}
+	src := mergeTeamSrc{Members: []mergeMember{{ID: 3, Name: "Carol"}, {ID: 2, Name: "Robert"}}}
+
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []mergeMemberDst{{ID: 2, Name: "Robert", Notes: "ops"}, {ID: 3, Name: "Carol"}}
+	if !reflect.DeepEqual(dst.Members, want) {
+		t.Errorf("expected %+v, got %+v", want, dst.Members)
+	}
+}
+
+func TestMergeStrategy_SliceByKeyPointers(t *testing.T) {
+	type Src struct{ Members []*mergeMember }
+	type Dst struct {
+		Members []*mergeMemberDst `mapkey:"ID"`
+	}
+
+	bob := &mergeMemberDst{ID: 2, Name: "Bob", Notes: "ops"}
+	dst := Dst{Members: []*mergeMemberDst{bob}}
+	src := Src{Members: []*mergeMember{{ID: 2, Name: "Robert"}, nil, {ID: 4, Name: "Dan"}}}
+
+	if err := MapWithOptions(&dst, src, WithPruneMissing()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dst.Members) != 3 || dst.Members[0] != bob || dst.Members[1] != nil || dst.Members[2].Name != "Dan" {
+		t.Fatalf("unexpected members %+v", dst.Members)
+	}
+	if bob.Name != "Robert" || bob.Notes != "ops" {
+		t.Errorf("expected pointee to be updated in place, got %+v", bob)
+	}
+}
+
+func TestMergeStrategy_SliceByIndex(t *testing.T) {
+	type Src struct{ Members []mergeMember }
+	dst := struct{ Members []mergeMemberDst }{Members: []mergeMemberDst{
+		{ID: 1, Notes: "a"}, {ID: 2, Notes: "b"}, {ID: 3, Notes: "c"},
+	}}
+	src := Src{Members: []mergeMember{{ID: 10, Name: "x"}, {ID: 20, Name: "y"}}}
+
+	if err := MapWithOptions(&dst, src, WithMergeStrategy(MergeByKey)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []mergeMemberDst{{ID: 10, Name: "x", Notes: "a"}, {ID: 20, Name: "y", Notes: "b"}, {ID: 3, Notes: "c"}}
+	if !reflect.DeepEqual(dst.Members, want) {
+		t.Errorf("expected %+v, got %+v", want, dst.Members)
+	}
+
+	if err := MapWithOptions(&dst, src, WithMergeStrategy(MergeByKey), WithPruneMissing()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst.Members) != 2 {
+		t.Errorf("expected prune to truncate to 2 elements, got %+v", dst.Members)
+	}
+}
+
+func TestMergeStrategy_Maps(t *testing.T) {
+	type Src struct{ Labels map[string]string }
+	type Dst struct{ Labels map[string]string }
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{"replace", nil, map[string]string{"b": "2", "c": "3"}},
+		{"append", []Option{WithMergeStrategy(MergeAppend)}, map[string]string{"a": "x", "b": "2", "c": "3"}},
+		{"union", []Option{WithMergeStrategy(MergeUnion)}, map[string]string{"a": "x", "b": "y", "c": "3"}},
+		{"merge", []Option{WithMergeStrategy(MergeByKey)}, map[string]string{"a": "x", "b": "2", "c": "3"}},
+		{"merge prune", []Option{WithMergeStrategy(MergeByKey), WithPruneMissing()}, map[string]string{"b": "2", "c": "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := map[string]string{"a": "x", "b": "y"}
+			dst := Dst{Labels: existing}
+
+			if err := MapWithOptions(&dst, Src{Labels: map[string]string{"b": "2", "c": "3"}}, tt.opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(dst.Labels, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, dst.Labels)
+			}
+			if len(existing) != 2 || existing["b"] != "y" {
+				t.Errorf("expected original map to be untouched, got %v", existing)
+			}
+		})
+	}
+}
+
+func TestMergeStrategy_MapPointerValues(t *testing.T) {
+	type Src struct{ Members map[string]*mergeMember }
+	type Dst struct {
+		Members map[string]*mergeMemberDst `mapmerge:"merge"`
+	}
+
+	alice := &mergeMemberDst{ID: 1, Name: "Alice", Notes: "lead"}
+	dst := Dst{Members: map[string]*mergeMemberDst{"alice": alice}}
+	src := Src{Members: map[string]*mergeMember{"alice": {ID: 1, Name: "Alicia"}, "bob": {ID: 2, Name: "Bob"}}}
+
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Members["alice"] != alice || alice.Name != "Alicia" || alice.Notes != "lead" {
+		t.Errorf("expected entry to be merged in place, got %+v", dst.Members["alice"])
+	}
+	if dst.Members["bob"] == nil || dst.Members["bob"].Name != "Bob" {
+		t.Errorf("expected new entry, got %+v", dst.Members["bob"])
+	}
+}
+
+func TestMergeStrategy_NilSourceKeepsDestination(t *testing.T) {
+	dst := mergeTeamDst{
+		Members: []mergeMemberDst{{ID: 1}},
+		Tags:    []string{"a"},
+		Labels:  map[string]string{"a": "x"},
+	}
+
+	if err := MapWithOptions(&dst, mergeTeamSrc{}, WithMergeStrategy(MergeAppend)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst.Members) != 1 || len(dst.Tags) != 1 || len(dst.Labels) != 1 {
+		t.Errorf("expected nil sources to keep the destination, got %+v", dst)
+	}
+}
+
+func TestMergeStrategy_PointerFields(t *testing.T) {
+	type Src struct {
+		Members *[]mergeMember
+		Tags    []string
+		Labels  map[string]string
+		Empty   *[]string
+	}
+	type Dst struct {
+		Members []mergeMemberDst   `mapkey:"ID"`
+		Tags    *[]string          `mapmerge:"union"`
+		Labels  *map[string]string `mapmerge:"merge"`
+		Empty   *[]string          `mapmerge:"append"`
+	}
+
+	tags := []string{"a"}
+	dst := Dst{
+		Members: []mergeMemberDst{{ID: 1, Name: "old", Notes: "keep"}},
+		Tags:    &tags,
+		Empty:   &[]string{"x"},
+	}
+	src := Src{
+		Members: &[]mergeMember{{ID: 1, Name: "new"}, {ID: 2, Name: "added"}},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"k": "v"},
+	}
+
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []mergeMemberDst{{ID: 1, Name: "new", Notes: "keep"}, {ID: 2, Name: "added"}}; !reflect.DeepEqual(dst.Members, want) {
+		t.Errorf("expected pointer sources to be merged by key, got %+v", dst.Members)
+	}
+	if !reflect.DeepEqual(*dst.Tags, []string{"a", "b"}) || dst.Tags == &tags || !reflect.DeepEqual(tags, []string{"a"}) {
+		t.Errorf("expected the pointee to be merged into a new pointer, got %v", *dst.Tags)
+	}
+	if dst.Labels == nil || !reflect.DeepEqual(*dst.Labels, map[string]string{"k": "v"}) {
+		t.Errorf("expected a nil destination pointer to receive the merged map, got %v", dst.Labels)
+	}
+	if !reflect.DeepEqual(*dst.Empty, []string{"x"}) {
+		t.Errorf("expected a nil source pointer to keep the destination, got %v", *dst.Empty)
+	}
+
+	// The pointee itself is updated with pointer reuse
+	dst.Tags = &tags
+	if err := MapWithOptions(&dst, Src{Tags: []string{"c"}}, WithReusePointers()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Tags != &tags || !reflect.DeepEqual(tags, []string{"a", "c"}) {
+		t.Errorf("expected the existing pointee to be merged into, got %v", *dst.Tags)
+	}
+}
+
+func TestMergeStrategy_OptionalSource(t *testing.T) {
+	type Src struct{ Tags Optional[[]string] }
+	dst := mergeTeamDst{Tags: []string{"a"}}
+
+	if err := Map(&dst, Src{Tags: Some([]string{"a", "b"})}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"a", "b"}) {
+		t.Errorf("expected the optional value to be merged, got %v", dst.Tags)
+	}
+
+	// Null still clears the field
+	if err := Map(&dst, Src{Tags: Null[[]string]()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Tags != nil {
+		t.Errorf("expected null to clear the field, got %v", dst.Tags)
+	}
+}
+
+func TestMergeStrategy_TagOverridesOption(t *testing.T) {
+	type Src struct{ Tags, Other []string }
+	type Dst struct {
+		Tags  []string `mapmerge:"replace"`
+		Other []string
+	}
+	dst := Dst{Tags: []string{"a"}, Other: []string{"a"}}
+
+	if err := MapWithOptions(&dst, Src{Tags: []string{"b"}, Other: []string{"b"}}, WithMergeStrategy(MergeAppend)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"b"}) || !reflect.DeepEqual(dst.Other, []string{"a", "b"}) {
+		t.Errorf("unexpected result %+v", dst)
+	}
+}
+
+func TestMergeStrategy_Errors(t *testing.T) {
+	type Src struct{ Members []mergeMember }
+
+	tests := []struct {
+		name string
+		dst  any
+	}{
+		{"unknown strategy", &struct {
+			Members []mergeMemberDst `mapmerge:"zip"`
+		}{}},
+		{"unknown option", &struct {
+			Members []mergeMemberDst `mapmerge:"merge,drop"`
+		}{}},
+		{"key with append", &struct {
+			Members []mergeMemberDst `mapmerge:"append" mapkey:"ID"`
+		}{}},
+		{"missing key field", &struct {
+			Members []mergeMemberDst `mapkey:"Email"`
+		}{}},
+		{"non-struct elements", &struct {
+			Members []int `mapkey:"ID"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Map(tt.dst, Src{Members: []mergeMember{{ID: 1}}})
+			var mappingErr *MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("expected *MappingError, got %v", err)
+			}
+			if !errors.Is(err, ErrInvalidMerge) || mappingErr.FieldPath != "Members" {
+				t.Errorf("expected invalid merge error at Members, got %v", err)
+			}
+		})
+	}
+
+	type Dst struct {
+		Count int `mapmerge:"append"`
+	}
+	var dst Dst
+	if err := Map(&dst, struct{ Count int }{Count: 1}); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("expected invalid merge error for a non-collection field, got %v", err)
+	}
+}
+
+func TestMergeStrategy_ElementErrorPaths(t *testing.T) {
+	type Item struct {
+		ID  int
+		Qty string `mapconv:"int"`
+	}
+	type ItemDst struct {
+		ID  int
+		Qty int
+	}
+	type Src struct{ Items []Item }
+	type Dst struct {
+		Items []ItemDst `mapkey:"ID"`
+	}
+
+	dst := Dst{Items: []ItemDst{{ID: 1}}}
+	err := MapWithOptions(&dst, Src{Items: []Item{{ID: 1, Qty: "x"}, {ID: 2, Qty: "2"}, {ID: 3, Qty: "y"}}}, WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected two collected errors, got %v", err)
+	}
+	if errs[0].FieldPath != "Items[0].Qty" || errs[1].FieldPath != "Items[2].Qty" {
+		t.Errorf("unexpected paths %v", collectedPaths(errs))
+	}
+	if len(dst.Items) != 2 || dst.Items[1].Qty != 2 {
+		t.Errorf("expected valid elements to be merged, got %+v", dst.Items)
+	}
+}
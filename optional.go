@@ -0,0 +1,166 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Optional is a tri-state value for patches: it is either absent (the zero
+// value), explicitly null, or set to a value, which may itself be a zero value.
+//
+// When an Optional source field is mapped into a field of another type, an
+// absent Optional leaves the destination unchanged, a null one sets the
+// destination to its zero value or nil, and a set one is mapped like a plain
+// field of type T, including mapconv conversions and pointer handling:
+//
+//	type PatchUser struct {
+//	    Name  mapper.Optional[string]
+//	    Email mapper.Optional[*string]
+//	}
+//
+//	patch := PatchUser{Name: mapper.Some(""), Email: mapper.Null[*string]()}
+//	err := mapper.Map(&user, patch)
+//	// user.Name == "", user.Email == nil, other fields unchanged
+//
+// Optional implements [json.Marshaler] and [json.Unmarshaler], so a missing
+// JSON member decodes as absent and a JSON null as null.
+type Optional[T any] struct {
+	value T
+	set   bool // present, either with a value or null
+	null  bool
+}
+
+// Some returns an Optional set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Null returns an explicitly null Optional.
+func Null[T any]() Optional[T] {
+	return Optional[T]{set: true, null: true}
+}
+
+// Get returns the value and true when o is set to a value. It returns the
+// zero value and false when o is absent or null.
+func (o Optional[T]) Get() (T, bool) {
+	if !o.set || o.null {
+		var zero T
+		return zero, false
+	}
+	return o.value, true
+}
+
+// IsSet reports whether o is present, either with a value or null.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsNull reports whether o is explicitly null.
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// MarshalJSON encodes a set Optional as its value and an absent or null one
+// as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set || o.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes null as a null Optional and any other value as a set
+// one. Members missing from the JSON input leave the Optional absent.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// optionalInfo describes a struct type that follows the optional contract:
+// a Get() (T, bool) method and, to distinguish null from absent, an optional
+// IsSet() bool method. Methods may have value or pointer receivers.
+type optionalInfo struct {
+	ptrRecv bool // methods are looked up on the pointer type
+	get     int  // method index of Get
+	isSet   int  // method index of IsSet, or -1
+}
+
+// optionalTypes caches optionalOf results; a nil *optionalInfo marks a
+// type that is not optional.
+var optionalTypes sync.Map
+
+// optionalOf returns the optional contract of t, or nil when t does not
+// follow it. Only struct types are recognized.
+func optionalOf(t reflect.Type) *optionalInfo {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := optionalTypes.Load(t); ok {
+		return cached.(*optionalInfo)
+	}
+
+	var info *optionalInfo
+	for _, recv := range []reflect.Type{t, reflect.PointerTo(t)} {
+		get, ok := recv.MethodByName("Get")
+		if !ok || get.Type.NumIn() != 1 || get.Type.NumOut() != 2 || get.Type.Out(1).Kind() != reflect.Bool {
+			continue
+		}
+		info = &optionalInfo{ptrRecv: recv != t, get: get.Index, isSet: -1}
+		if isSet, ok := recv.MethodByName("IsSet"); ok &&
+			isSet.Type.NumIn() == 1 && isSet.Type.NumOut() == 1 && isSet.Type.Out(0).Kind() == reflect.Bool {
+			info.isSet = isSet.Index
+		}
+		break
+	}
+
+	actual, _ := optionalTypes.LoadOrStore(t, info)
+	return actual.(*optionalInfo)
+}
+
+// readOptional returns the value of the optional v. present is false when v
+// is absent; set is false when v is absent or null. Without an IsSet method,
+// an optional without a value is treated as absent.
+func readOptional(v reflect.Value, info *optionalInfo) (value reflect.Value, present, set bool) {
+	recv := v
+	if info.ptrRecv {
+		if !recv.CanAddr() {
+			recv = reflect.New(v.Type()).Elem()
+			recv.Set(v)
+		}
+		recv = recv.Addr()
+	}
+
+	out := recv.Method(info.get).Call(nil)
+	if out[1].Bool() {
+		return out[0], true, true
+	}
+	if info.isSet >= 0 {
+		return reflect.Value{}, recv.Method(info.isSet).Call(nil)[0].Bool(), false
+	}
+	return reflect.Value{}, false, false
+}
+
+// assignOptional maps the optional src into dst: absent optionals are
+// skipped, null ones set dst to its zero value and set ones are mapped as
+// plain values.
+func assignOptional(dst, src reflect.Value, info *optionalInfo, srcStructType, dstStructType reflect.Type, basePath, fieldName, convertTo string, cfg *config, depth int) error {
+	value, present, set := readOptional(src, info)
+	if !present {
+		return nil
+	}
+	if !set {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	return assignNestedValue(dst, value, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth)
+}
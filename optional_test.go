@@ -0,0 +1,270 @@
+package mapper
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type optionalPatch struct {
+	Name    Optional[string]
+	Age     Optional[int]
+	Email   Optional[*string]
+	Score   Optional[string] `mapconv:"float64"`
+	Address Optional[optionalAddress]
+}
+
+type optionalAddress struct {
+	City string
+}
+
+type optionalUser struct {
+	Name    string
+	Age     int
+	Email   *string
+	Score   float64
+	Address *optionalAddress
+}
+
+func TestOptional_States(t *testing.T) {
+	var absent Optional[int]
+	if _, ok := absent.Get(); ok || absent.IsSet() || absent.IsNull() {
+		t.Errorf("expected zero Optional to be absent, got %+v", absent)
+	}
+
+	null := Null[int]()
+	if _, ok := null.Get(); ok || !null.IsSet() || !null.IsNull() {
+		t.Errorf("expected null Optional, got %+v", null)
+	}
+
+	some := Some(0)
+	if v, ok := some.Get(); !ok || v != 0 || !some.IsSet() || some.IsNull() {
+		t.Errorf("expected Optional set to 0, got %+v", some)
+	}
+}
+
+func TestOptional_AbsentLeavesDestination(t *testing.T) {
+	email := "old@example.com"
+	dst := optionalUser{
+		Name:    "Alice",
+		Age:     30,
+		Email:   &email,
+		Score:   1.5,
+		Address: &optionalAddress{City: "Paris"},
+	}
+	if err := Map(&dst, optionalPatch{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "Alice" {
+		t.Errorf("expected Name = 'Alice', got %q", dst.Name)
+	}
+	if dst.Age != 30 {
+		t.Errorf("expected Age = 30, got %d", dst.Age)
+	}
+	if dst.Email != &email {
+		t.Errorf("expected Email to be kept, got %v", dst.Email)
+	}
+	if dst.Score != 1.5 {
+		t.Errorf("expected Score = 1.5, got %v", dst.Score)
+	}
+	if dst.Address == nil || dst.Address.City != "Paris" {
+		t.Errorf("expected Address.City = 'Paris', got %+v", dst.Address)
+	}
+}
+
+func TestOptional_SetValuesIncludingZero(t *testing.T) {
+	email := "new@example.com"
+	patch := optionalPatch{
+		Name:    Some(""),
+		Age:     Some(0),
+		Email:   Some(&email),
+		Score:   Some("2.5"),
+		Address: Some(optionalAddress{City: "Rome"}),
+	}
+
+	oldEmail := "old@example.com"
+	dst := optionalUser{
+		Name:    "Alice",
+		Age:     30,
+		Email:   &oldEmail,
+		Score:   1.5,
+		Address: &optionalAddress{City: "Paris"},
+	}
+	if err := MapWithOptions(&dst, patch, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "" {
+		t.Errorf("expected Name = '', got %q", dst.Name)
+	}
+	if dst.Age != 0 {
+		t.Errorf("expected Age = 0, got %d", dst.Age)
+	}
+	if dst.Email == nil || *dst.Email != email {
+		t.Errorf("expected Email = %q, got %v", email, dst.Email)
+	}
+	if dst.Score != 2.5 {
+		t.Errorf("expected Score = 2.5, got %v", dst.Score)
+	}
+	if dst.Address == nil || dst.Address.City != "Rome" {
+		t.Errorf("expected Address.City = 'Rome', got %+v", dst.Address)
+	}
+	if dst.Email == &email {
+		t.Error("expected pointer value to be deep copied")
+	}
+}
+
+func TestOptional_NullClearsDestination(t *testing.T) {
+	patch := optionalPatch{
+		Name:    Null[string](),
+		Email:   Null[*string](),
+		Address: Null[optionalAddress](),
+	}
+
+	email := "old@example.com"
+	dst := optionalUser{
+		Name:    "Alice",
+		Age:     30,
+		Email:   &email,
+		Address: &optionalAddress{City: "Paris"},
+	}
+	if err := Map(&dst, patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "" || dst.Email != nil || dst.Address != nil {
+		t.Errorf("expected null optionals to clear the destination, got %+v", dst)
+	}
+	if dst.Age != 30 {
+		t.Errorf("expected absent Age to be kept, got %d", dst.Age)
+	}
+}
+
+func TestOptional_NestedAndPointerSources(t *testing.T) {
+	type Inner struct{ Count Optional[int] }
+	type InnerDst struct{ Count int }
+	type Src struct {
+		Inner  Inner
+		PtrOpt *Optional[string]
+	}
+	type Dst struct {
+		Inner  InnerDst
+		PtrOpt string
+	}
+
+	dst := Dst{Inner: InnerDst{Count: 5}, PtrOpt: "keep"}
+	if err := Map(&dst, Src{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Inner.Count != 5 || dst.PtrOpt != "keep" {
+		t.Errorf("expected absent optionals to be skipped, got %+v", dst)
+	}
+
+	null := Null[string]()
+	if err := Map(&dst, Src{Inner: Inner{Count: Some(0)}, PtrOpt: &null}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Inner.Count != 0 || dst.PtrOpt != "" {
+		t.Errorf("expected nested set and null optionals to apply, got %+v", dst)
+	}
+}
+
+func TestOptional_SameTypeIsCopied(t *testing.T) {
+	type Form struct{ Name Optional[string] }
+
+	var dst Form
+	if err := MapWithOptions(&dst, Form{Name: Null[string]()}, WithIgnoreZeroSource()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !dst.Name.IsNull() {
+		t.Errorf("expected Optional to be copied, got %+v", dst.Name)
+	}
+}
+
+// nullableInt follows the optional contract with pointer receivers.
+type nullableInt struct {
+	v     int
+	valid bool
+	set   bool
+}
+
+func (n *nullableInt) Get() (int, bool) { return n.v, n.valid }
+func (n *nullableInt) IsSet() bool      { return n.set }
+
+// maybeString follows the optional contract without IsSet.
+type maybeString struct{ s *string }
+
+func (m maybeString) Get() (string, bool) {
+	if m.s == nil {
+		return "", false
+	}
+	return *m.s, true
+}
+
+func TestOptional_CustomContract(t *testing.T) {
+	type Src struct {
+		Count nullableInt
+		Label maybeString
+	}
+	type Dst struct {
+		Count int64
+		Label string
+	}
+
+	dst := Dst{Count: 9, Label: "keep"}
+	if err := Map(&dst, Src{Count: nullableInt{v: 3, valid: true, set: true}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Count != 3 || dst.Label != "keep" {
+		t.Errorf("expected custom optionals to be unwrapped, got %+v", dst)
+	}
+
+	if err := Map(&dst, Src{Count: nullableInt{set: true}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Count != 0 {
+		t.Errorf("expected null custom optional to clear Count, got %d", dst.Count)
+	}
+}
+
+func TestOptional_ConversionErrorPath(t *testing.T) {
+	var dst optionalUser
+	err := Map(&dst, optionalPatch{Score: Some("high")})
+
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %v", err)
+	}
+	if mappingErr.FieldPath != "Score" || mappingErr.Code != CodeConversionFailed {
+		t.Errorf("unexpected error %+v", mappingErr)
+	}
+}
+
+func TestOptional_JSON(t *testing.T) {
+	var patch struct {
+		Name  Optional[string] `json:"name"`
+		Email Optional[string] `json:"email"`
+		Age   Optional[int]    `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(`{"name": "", "email": null}`), &patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v, ok := patch.Name.Get(); !ok || v != "" {
+		t.Errorf("expected name to be set to empty, got %+v", patch.Name)
+	}
+	if !patch.Email.IsNull() {
+		t.Errorf("expected email to be null, got %+v", patch.Email)
+	}
+	if patch.Age.IsSet() {
+		t.Errorf("expected age to be absent, got %+v", patch.Age)
+	}
+
+	out, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != `{"name":"","email":null,"age":null}` {
+		t.Errorf("unexpected JSON %s", out)
+	}
+}
@@ -1,5 +1,10 @@
 package mapper
 
+import (
+	"context"
+	"reflect"
+)
+
 // DefaultMaxDepth is the default maximum nesting depth for struct mapping.
 // This limit prevents stack overflow from deeply nested or circular references.
 // The default value of 64 is sufficient for most real-world use cases.
@@ -9,9 +14,49 @@ type config struct {
 	tagName          string
 	ignoreZeroSource bool
 	strictMode       bool
+	strictTypes      map[reflect.Type]struct{}
+	strictSource     bool
 	maxDepth         int
+	converters       map[converterKey]converterFunc
+	factories        map[reflect.Type]factoryFunc
+	implementations  map[implKey]reflect.Type
+	discriminators   map[reflect.Type]*discriminator
+	collectErrors    bool
+	maxErrors        int
+	merge            mergeSpec
+	pointers         pointerMode
+	report           *Report
+	preserveRefs     bool
+	// registered is set for the package-level functions, which also consult
+	// the converters, factories, implementations and discriminators added
+	// with the Register functions
+	registered bool
+
+	// errorCount is per-call state: the number of errors collected so far
+	errorCount int
+	// refs is per-call state: the destination pointer created for each
+	// source pointer, with WithPreserveReferences
+	refs map[refKey]reflect.Value
+	// ctx is per-call state: the context passed to MapContext, or nil
+	ctx context.Context
+
+	// mapper owns the caches used by the call
+	mapper *Mapper
 }
 
+// pointerMode selects whether pointer destinations are mapped into their
+// existing pointee or into a newly allocated value.
+type pointerMode uint8
+
+const (
+	// pointerDefault allocates, except that patch semantics reuse struct pointees.
+	pointerDefault pointerMode = iota
+	// pointerReuse maps into every existing non-nil pointee.
+	pointerReuse
+	// pointerAlloc always allocates, set by the `mapptr:"new"` tag.
+	pointerAlloc
+)
+
 // defaultConfig returns default configuration values.
 // Returns a value (not pointer) to enable stack allocation in the caller.
 func defaultConfig() config {
@@ -23,7 +68,7 @@ func defaultConfig() config {
 	}
 }
 
-// Option configures the behavior of [MapWithOptions].
+// Option configures the behavior of [MapWithOptions] and [New].
 // Options are applied in the order they are passed.
 type Option func(*config)
 
@@ -81,6 +126,12 @@ func WithTagName(tag string) Option {
 //	// existing = {Name: "Alicia", Email: "rafa@old.com", Age: 25}
 //
 // Without this option, the empty string and zero would overwrite the existing values.
+//
+// Patch semantics apply at every level, giving a deep merge: nested structs
+// are merged field by field, pointer-to-struct fields are mapped into the
+// existing pointee when the destination is non-nil, and struct values of maps
+// are merged into the existing entries with the same key while other entries
+// are kept.
 func WithIgnoreZeroSource() Option {
 	return func(c *config) {
 		c.ignoreZeroSource = true
@@ -92,6 +143,10 @@ func WithIgnoreZeroSource() Option {
 //
 // This is useful for ensuring that all destination fields are populated,
 // catching mistakes like typos in field names or missing fields in the source.
+// Strict mode applies at every level: nested structs, slice elements and map
+// values are checked too, and errors report the full path, such as
+// "Address.Zip" or "Items[2].Sku". Use [WithStrictTypes] to check only
+// selected struct types.
 //
 // Example:
 //
@@ -114,6 +169,155 @@ func WithStrictMode() Option {
 	}
 }
 
+// WithStrictTypes enables strict mode only for destination structs of the
+// given types, wherever they appear: at the top level, nested in other
+// structs, or as slice elements and map values. Other structs are mapped
+// leniently. Each sample may be a struct value or a pointer to a struct;
+// other values are ignored.
+//
+// Example:
+//
+//	// Only report unmatched fields inside addresses
+//	err := mapper.MapWithOptions(&order, req, mapper.WithStrictTypes(Address{}))
+//
+// Multiple calls add to the set of strict types. [WithStrictMode] takes
+// precedence and checks every struct.
+func WithStrictTypes(samples ...any) Option {
+	types := make([]reflect.Type, 0, len(samples))
+	for _, sample := range samples {
+		t := reflect.TypeOf(sample)
+		if t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t != nil && t.Kind() == reflect.Struct {
+			types = append(types, t)
+		}
+	}
+
+	return func(c *config) {
+		c.strictTypes = copyMap(c.strictTypes, len(types))
+		for _, t := range types {
+			c.strictTypes[t] = struct{}{}
+		}
+	}
+}
+
+// WithStrictSource configures the mapper to return an error when an exported
+// source field is not mapped to any destination field, by name or tag.
+//
+// This is the inverse of [WithStrictMode] and is useful when converting
+// domain models to DTOs: a field added to the domain model cannot be silently
+// dropped. Nested structs, slice elements and map values are checked too, and
+// errors report the full path of the source field, such as "Address.Country".
+//
+// Fields that are intentionally not mapped can opt out with the mapstrict tag:
+//
+//	type User struct {
+//	    Name         string
+//	    PasswordHash string `mapstrict:"-"` // never exposed
+//	}
+//
+//	err := mapper.MapWithOptions(&dto, user, mapper.WithStrictSource())
+func WithStrictSource() Option {
+	return func(c *config) {
+		c.strictSource = true
+	}
+}
+
+// WithReusePointers configures the mapper to map into the value an existing
+// non-nil destination pointer points to, instead of allocating a new value
+// and replacing the pointer. A new value is only allocated when the
+// destination pointer is nil. This keeps the identity of objects shared with
+// caches or ORMs.
+//
+// The option applies to pointer fields, to pointer elements of slices, which
+// reuse the pointee at the same index, and to pointer values of maps, which
+// reuse the pointee under the same key. A nil source pointer still sets the
+// destination pointer to nil.
+//
+// Example:
+//
+//	profile := user.Profile // *Profile tracked by the ORM
+//	err := mapper.MapWithOptions(&user, req, mapper.WithReusePointers())
+//	// user.Profile == profile, with its fields updated
+//
+// Individual destination fields can opt in with `mapptr:"reuse"` or opt out
+// with `mapptr:"new"`; the tag applies to every pointer mapped within the field.
+func WithReusePointers() Option {
+	return func(c *config) {
+		c.pointers = pointerReuse
+	}
+}
+
+// WithPreserveReferences configures the mapper to track the source pointers
+// it visits during a call. A source pointer that is reached again maps to the
+// destination pointer created the first time, so:
+//   - fields, slice elements and map values that share an object in the
+//     source share one copy in the destination
+//   - cycles, such as back-pointers from children to their parent or
+//     doubly-linked lists, are mapped into the same cycle instead of failing
+//     with a depth error
+//
+// Example:
+//
+//	type Node struct {
+//	    Name     string
+//	    Parent   *Node
+//	    Children []*Node
+//	}
+//
+//	var dst NodeDTO
+//	err := mapper.MapWithOptions(&dst, &root, mapper.WithPreserveReferences())
+//	// dst.Children[0].Parent == &dst
+//
+// Pointers are tracked per source address and per source and destination
+// pointer type. The root is tracked when src is passed as a pointer.
+// [WithMaxDepth] still limits the nesting of values that are not shared.
+func WithPreserveReferences() Option {
+	return func(c *config) {
+		c.preserveRefs = true
+	}
+}
+
+// refKey identifies a source pointer mapped to a destination pointer type.
+type refKey struct {
+	addr uintptr
+	src  reflect.Type
+	dst  reflect.Type
+}
+
+// mappedRef returns the destination pointer of type dstType already created
+// for the source pointer src during the call, with WithPreserveReferences.
+func (c *config) mappedRef(src reflect.Value, dstType reflect.Type) (reflect.Value, bool) {
+	if !c.preserveRefs || c.refs == nil {
+		return reflect.Value{}, false
+	}
+	v, ok := c.refs[refKey{addr: src.Pointer(), src: src.Type(), dst: dstType}]
+	return v, ok
+}
+
+// rememberRef records dst as the destination pointer of the source pointer
+// src. It is called before the pointee is mapped so cycles find it.
+func (c *config) rememberRef(src, dst reflect.Value) {
+	if !c.preserveRefs {
+		return
+	}
+	if c.refs == nil {
+		c.refs = make(map[refKey]reflect.Value)
+	}
+	c.refs[refKey{addr: src.Pointer(), src: src.Type(), dst: dst.Type()}] = dst
+}
+
+// isStrict reports whether unmatched fields of the destination struct type t
+// must be reported.
+func (c *config) isStrict(t reflect.Type) bool {
+	if c.strictMode {
+		return true
+	}
+	_, ok := c.strictTypes[t]
+	return ok
+}
+
 // WithMaxDepth sets the maximum nesting depth for struct mapping. This prevents
 // stack overflow from deeply nested structures or circular references.
 //
@@ -141,3 +345,45 @@ func WithMaxDepth(depth int) Option {
 		}
 	}
 }
+
+// WithCollectErrors configures the mapper to keep mapping after a field fails
+// and to report every failure at once. This is useful for user-submitted data,
+// where reporting one invalid field per round trip is frustrating.
+//
+// In this mode mapping errors are returned as [MappingErrors], which lists a
+// [*MappingError] with its FieldPath for each failure. Fields that failed are
+// left partially mapped or unchanged. Errors detected before mapping starts,
+// such as a nil destination, are still returned as a single [*MappingError].
+//
+// Example:
+//
+//	err := mapper.MapWithOptions(&form, req, mapper.WithCollectErrors())
+//	var errs mapper.MappingErrors
+//	if errors.As(err, &errs) {
+//	    for _, e := range errs {
+//	        fmt.Printf("%s: %s\n", e.FieldPath, e.Reason)
+//	    }
+//	}
+func WithCollectErrors() Option {
+	return func(c *config) {
+		c.collectErrors = true
+	}
+}
+
+// WithMaxErrors enables the collect mode of [WithCollectErrors] and stops
+// mapping once n errors have been collected.
+//
+// Values less than or equal to 0 collect every error.
+//
+// Example:
+//
+//	// Report at most 10 invalid fields
+//	err := mapper.MapWithOptions(&form, req, mapper.WithMaxErrors(10))
+func WithMaxErrors(n int) Option {
+	return func(c *config) {
+		c.collectErrors = true
+		if n > 0 {
+			c.maxErrors = n
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// patchSource is reported as the source type of merge patch errors.
+const patchSource = "json merge patch"
+
+// ApplyMergePatch applies a JSON Merge Patch document (RFC 7396) to the
+// struct dst points to.
+//
+// Patch members are matched to destination fields by name or by the
+// configured tag name, ignoring tag options such as ",omitempty", so
+// WithTagName("json") follows the JSON names of the struct. Members that are
+// absent leave their field unchanged, and:
+//   - null sets the field to its zero value or nil, and deletes map entries
+//   - objects are merged recursively into structs, struct pointers and maps
+//   - arrays replace slices
+//   - JSON strings are parsed into numeric and boolean fields with the same
+//     parsers as the mapconv tag
+//   - JSON strings, numbers and booleans use a converter from string,
+//     float64 or bool to the field type instead, if one is registered with
+//     [RegisterConverter] or supplied with [WithConverter]
+//   - fields whose type implements [json.Unmarshaler] decode the value
+//     themselves; an [Optional] field receives null as an explicit null
+//   - pointer fields follow [WithReusePointers] and the mapptr tag; with
+//     `mapptr:"new"`, objects merge into a copy of the existing struct
+//
+// Example:
+//
+//	// PATCH /users/42 {"name": "Alicia", "email": null, "address": {"zip": "75001"}}
+//	err := mapper.ApplyMergePatch(&user, body, mapper.WithTagName("json"))
+//
+// Failures are returned as a [*MappingError] with the full Go field path,
+// such as "Address.Zip" or "Tags[2]", and the options for collecting errors,
+// maximum depth and [WithStrictSource], which reports patch members that
+// match no field, apply as in [MapWithOptions].
+func ApplyMergePatch(dst any, patch []byte, opts ...Option) error {
+	return defaultMapper.ApplyMergePatch(dst, patch, opts...)
+}
+
+// ApplyMergePatch applies a JSON Merge Patch document to dst using the
+// options the Mapper was created with, followed by opts. See [ApplyMergePatch].
+func (m *Mapper) ApplyMergePatch(dst any, patch []byte, opts ...Option) error {
+	cfg := m.callConfig(opts)
+	return runMergePatch(dst, patch, &cfg)
+}
+
+func runMergePatch(dst any, patch []byte, cfg *config) error {
+	dstVal := reflect.ValueOf(dst)
+	if dst == nil || dstVal.Kind() != reflect.Ptr || dstVal.IsNil() || dstVal.Elem().Kind() != reflect.Struct {
+		code := CodeInvalidInput
+		if dst == nil || dstVal.Kind() == reflect.Ptr && dstVal.IsNil() {
+			code = CodeNilInput
+		}
+		return &MappingError{
+			SrcType:   patchSource,
+			DstType:   typeOf(dst),
+			FieldPath: "",
+			Reason:    "dst must be a non-nil pointer to struct",
+			Code:      code,
+		}
+	}
+
+	dstElem := dstVal.Elem()
+	dstType := dstElem.Type()
+
+	if jsonKind(patch) != "object" {
+		return &MappingError{
+			SrcType:   patchSource,
+			DstType:   dstType.String(),
+			FieldPath: "",
+			Reason:    "merge patch must be a JSON object",
+			Code:      CodeInvalidInput,
+		}
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &obj); err != nil {
+		return &MappingError{
+			SrcType:   patchSource,
+			DstType:   dstType.String(),
+			FieldPath: "",
+			Reason:    "invalid merge patch: " + err.Error(),
+			Code:      CodeInvalidInput,
+			Err:       err,
+		}
+	}
+
+	return applyPatchObject(dstElem, obj, dstType, "", cfg, cfg.maxDepth)
+}
+
+// applyPatchObject merges the members of obj into the struct dst.
+func applyPatchObject(dst reflect.Value, obj map[string]json.RawMessage, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	if depth <= 0 {
+		return patchDepthError(dstStructType, fieldPath)
+	}
+
+	meta, err := cfg.mapper.getStructMeta(dst.Type(), cfg.tagName)
+	if err != nil {
+		return err
+	}
+
+	var errs MappingErrors
+	for _, key := range sortedKeys(obj) {
+		field := patchField(meta, key)
+		if field == nil {
+			if cfg.strictSource {
+				err := &MappingError{
+					SrcType:   patchSource,
+					DstType:   dstStructType.String(),
+					FieldPath: buildPath(fieldPath, key),
+					Reason:    "patch member is not mapped to any destination field",
+					Code:      CodeUnmappedSource,
+				}
+				if err := cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := applyPatchField(dst.Field(field.Index[0]), field, obj[key], dstStructType, buildPath(fieldPath, field.Name), cfg, depth); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return errs.orNil()
+}
+
+// applyPatchField applies the patch value raw to the struct field dst.
+func applyPatchField(dst reflect.Value, field *fieldMeta, raw json.RawMessage, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	// The mapptr tag overrides the pointer mode for everything below the field
+	if field.Pointers != pointerDefault && field.Pointers != cfg.pointers {
+		saved := cfg.pointers
+		cfg.pointers = field.Pointers
+		err := applyPatchValue(dst, raw, dstStructType, fieldPath, cfg, depth)
+		cfg.pointers = saved
+		return err
+	}
+	return applyPatchValue(dst, raw, dstStructType, fieldPath, cfg, depth)
+}
+
+// patchField returns the field of meta a patch member named key applies to.
+// Tag aliases match without their options, and fields tagged "-" never match.
+func patchField(meta *structMeta, key string) *fieldMeta {
+	if f, ok := meta.FieldsByTag[key]; ok && key != "-" {
+		return f
+	}
+	for _, f := range meta.Fields {
+		if alias, _, _ := strings.Cut(f.Tag, ","); alias == key && alias != "-" && alias != "" {
+			return f
+		}
+	}
+	if f, ok := meta.FieldsByName[key]; ok && f.Tag != "-" {
+		return f
+	}
+	return nil
+}
+
+// applyPatchValue applies the patch value raw to dst.
+func applyPatchValue(dst reflect.Value, raw json.RawMessage, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	if depth <= 0 {
+		return patchDepthError(dstStructType, fieldPath)
+	}
+
+	kind := jsonKind(raw)
+	dType := dst.Type()
+
+	// Scalars use a converter from the type encoding/json decodes them to,
+	// as a source field of that type would in Map
+	if cfg.hasConverters() {
+		if srcType := patchScalarType(kind); srcType != nil {
+			if conv, ok := cfg.lookupConverter(srcType, dType); ok {
+				return applyPatchConverter(conv, dst, raw, srcType, dstStructType, fieldPath, cfg)
+			}
+		}
+	}
+
+	// Types that decode themselves receive the raw value, except that null
+	// clears them unless they are optionals, which record the explicit null
+	if u, ok := dst.Addr().Interface().(json.Unmarshaler); ok && (kind != "null" || optionalOf(dType) != nil) {
+		if err := u.UnmarshalJSON(raw); err != nil {
+			return patchError(dstStructType, fieldPath, "cannot decode "+dType.String()+": "+err.Error(), CodeConversionFailed, err)
+		}
+		return nil
+	}
+
+	if kind == "null" {
+		dst.Set(reflect.Zero(dType))
+		return nil
+	}
+
+	switch dType.Kind() {
+	case reflect.Ptr:
+		elemType := dType.Elem()
+		// Objects merge into an existing struct pointee, as with patch
+		// semantics in Map; mapptr:"new" merges into a copy instead. A new
+		// pointer to a map starts from the existing entries, since
+		// applyPatchMap never modifies the map it merges into
+		ptr := dst
+		if dst.IsNil() || cfg.pointers == pointerAlloc || (elemType.Kind() != reflect.Struct && cfg.pointers != pointerReuse) {
+			ptr = cfg.newPointer(dType)
+			if !dst.IsNil() && (elemType.Kind() == reflect.Struct || elemType.Kind() == reflect.Map) {
+				ptr.Elem().Set(dst.Elem())
+			}
+		}
+		if err := applyPatchValue(ptr.Elem(), raw, dstStructType, fieldPath, cfg, depth-1); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if kind != "object" || json.Unmarshal(raw, &obj) != nil {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+		return applyPatchObject(dst, obj, dstStructType, fieldPath, cfg, depth-1)
+
+	case reflect.Map:
+		return applyPatchMap(dst, raw, kind, dstStructType, fieldPath, cfg, depth-1)
+
+	case reflect.Slice:
+		var items []json.RawMessage
+		if kind != "array" || json.Unmarshal(raw, &items) != nil {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+
+		newSlice := reflect.MakeSlice(dType, len(items), len(items))
+		cfg.initElements(newSlice)
+		var errs MappingErrors
+		for i, item := range items {
+			if err := applyPatchValue(newSlice.Index(i), item, dstStructType, buildSlicePath(fieldPath, i), cfg, depth-1); err != nil {
+				if err = cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+			}
+		}
+		dst.Set(newSlice)
+		return errs.orNil()
+
+	case reflect.Interface:
+		var v any
+		if dType.NumMethod() != 0 || json.Unmarshal(raw, &v) != nil {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+
+	case reflect.String:
+		var s string
+		if kind != "string" || json.Unmarshal(raw, &s) != nil {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+		dst.Set(reflect.ValueOf(s).Convert(dType))
+		return nil
+
+	default:
+		return applyPatchScalar(dst, raw, kind, dstStructType, fieldPath)
+	}
+}
+
+// patchScalarType returns the Go type encoding/json decodes JSON scalars of
+// the given kind to, or nil for objects, arrays and null.
+func patchScalarType(kind string) reflect.Type {
+	switch kind {
+	case "string":
+		return typeFor[string]()
+	case "number":
+		return typeFor[float64]()
+	case "boolean":
+		return typeFor[bool]()
+	default:
+		return nil
+	}
+}
+
+// applyPatchConverter decodes the scalar raw into srcType and applies conv.
+func applyPatchConverter(conv converterFunc, dst reflect.Value, raw json.RawMessage, srcType, dstStructType reflect.Type, fieldPath string, cfg *config) error {
+	src := reflect.New(srcType).Elem()
+	if err := json.Unmarshal(raw, src.Addr().Interface()); err != nil {
+		return patchIncompatible(jsonKind(raw), dst.Type(), dstStructType, fieldPath)
+	}
+	err := applyConverter(conv, dst, src, dstStructType, dstStructType, fieldPath, cfg)
+	if e, ok := err.(*MappingError); ok {
+		e.SrcType = patchSource
+	}
+	return err
+}
+
+// applyPatchScalar applies a JSON number, string or boolean to a numeric or
+// boolean field, parsing strings and numbers with the mapconv parsers.
+func applyPatchScalar(dst reflect.Value, raw json.RawMessage, kind string, dstStructType reflect.Type, fieldPath string) error {
+	dType := dst.Type()
+	target := dType.Kind().String()
+
+	parse, ok := stringParsers[target]
+	if !ok {
+		return patchIncompatible(kind, dType, dstStructType, fieldPath)
+	}
+
+	var str string
+	switch kind {
+	case "string":
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+	case "number":
+		if dType.Kind() == reflect.Bool {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+		str = string(bytes.TrimSpace(raw))
+	case "boolean":
+		if dType.Kind() != reflect.Bool {
+			return patchIncompatible(kind, dType, dstStructType, fieldPath)
+		}
+		str = string(bytes.TrimSpace(raw))
+	default:
+		return patchIncompatible(kind, dType, dstStructType, fieldPath)
+	}
+
+	val, err := parse(str)
+	if err != nil {
+		return &MappingError{
+			SrcType:   patchSource,
+			DstType:   dstStructType.String(),
+			FieldPath: fieldPath,
+			Reason:    "cannot convert \"" + str + "\" to " + target + ": " + err.Error(),
+			Code:      CodeConversionFailed,
+			Err:       err,
+		}
+	}
+	dst.Set(val.Convert(dType))
+	return nil
+}
+
+// applyPatchMap merges a JSON object into the map dst: null members delete
+// their key and other members are merged into the existing entry.
+func applyPatchMap(dst reflect.Value, raw json.RawMessage, kind string, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	dType := dst.Type()
+
+	var obj map[string]json.RawMessage
+	if kind != "object" || json.Unmarshal(raw, &obj) != nil {
+		return patchIncompatible(kind, dType, dstStructType, fieldPath)
+	}
+
+	keyType := dType.Key()
+	newMap := reflect.MakeMapWithSize(dType, dst.Len()+len(obj))
+	if !dst.IsNil() {
+		existing := dst.MapRange()
+		for existing.Next() {
+			newMap.SetMapIndex(existing.Key(), existing.Value())
+		}
+	}
+
+	var errs MappingErrors
+	for _, key := range sortedKeys(obj) {
+		keyPath := fieldPath + "[" + key + "]"
+
+		var mapKey reflect.Value
+		if keyType.Kind() == reflect.String {
+			mapKey = reflect.ValueOf(key).Convert(keyType)
+		} else if parse, ok := stringParsers[keyType.Kind().String()]; ok {
+			val, err := parse(key)
+			if err != nil {
+				err := &MappingError{
+					SrcType:   patchSource,
+					DstType:   dstStructType.String(),
+					FieldPath: keyPath,
+					Reason:    "cannot convert map key \"" + key + "\" to " + keyType.String() + ": " + err.Error(),
+					Code:      CodeConversionFailed,
+					Err:       err,
+				}
+				if err := cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+				continue
+			}
+			mapKey = val.Convert(keyType)
+		} else {
+			return patchError(dstStructType, fieldPath, "unsupported map key type: "+keyType.String(), CodeIncompatibleTypes, nil)
+		}
+
+		if jsonKind(obj[key]) == "null" {
+			newMap.SetMapIndex(mapKey, reflect.Value{})
+			continue
+		}
+
+		val := cfg.newValue(dType.Elem())
+		if existing := newMap.MapIndex(mapKey); existing.IsValid() {
+			val.Set(existing)
+		}
+		if err := applyPatchValue(val, obj[key], dstStructType, keyPath, cfg, depth); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+		newMap.SetMapIndex(mapKey, val)
+	}
+
+	dst.Set(newMap)
+	return errs.orNil()
+}
+
+// jsonKind returns the kind of the JSON value raw from its first byte:
+// "object", "array", "string", "number", "boolean" or "null".
+func jsonKind(raw []byte) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return ""
+	}
+	switch raw[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+func sortedKeys(obj map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func patchError(dstStructType reflect.Type, fieldPath, reason string, code ErrorCode, err error) error {
+	return &MappingError{
+		SrcType:   patchSource,
+		DstType:   dstStructType.String(),
+		FieldPath: fieldPath,
+		Reason:    reason,
+		Code:      code,
+		Err:       err,
+	}
+}
+
+func patchIncompatible(kind string, dType, dstStructType reflect.Type, fieldPath string) error {
+	return patchError(dstStructType, fieldPath, "incompatible patch value: JSON "+kind+" -> "+dType.String(), CodeIncompatibleTypes, nil)
+}
+
+func patchDepthError(dstStructType reflect.Type, fieldPath string) error {
+	return patchError(dstStructType, fieldPath, "maximum nesting depth exceeded (possible circular reference)", CodeDepthExceeded, nil)
+}
@@ -0,0 +1,303 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type patchTarget struct {
+	Name     string            `json:"name"`
+	Email    *string           `json:"email,omitempty"`
+	Age      int               `json:"age"`
+	Active   bool              `json:"active"`
+	Score    float32           `json:"score"`
+	Address  *patchAddressJSON `json:"address"`
+	Home     patchAddressJSON  `json:"home"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Limits   map[int]uint8     `json:"limits"`
+	Nickname Optional[string]  `json:"nickname"`
+	Updated  time.Time         `json:"updated"`
+	Secret   string            `json:"-"`
+}
+
+type patchAddressJSON struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	email := "alice@example.com"
+	address := &patchAddressJSON{City: "Paris", Zip: "75001"}
+	dst := patchTarget{
+		Name:    "Alice",
+		Email:   &email,
+		Age:     30,
+		Active:  true,
+		Address: address,
+		Home:    patchAddressJSON{City: "Lyon", Zip: "69001"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"team": "core", "tier": "gold"},
+		Secret:  "s3cret",
+	}
+
+	patch := []byte(`{
+		"name": "Alicia",
+		"email": null,
+		"age": "31",
+		"score": 1.5,
+		"address": {"zip": "75002"},
+		"home": {"city": "Nice"},
+		"tags": ["c"],
+		"labels": {"tier": null, "region": "eu"},
+		"limits": {"1": 10},
+		"nickname": null,
+		"updated": "2024-05-01T10:00:00Z"
+	}`)
+
+	if err := ApplyMergePatch(&dst, patch, WithTagName("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "Alicia" {
+		t.Errorf("expected Name = 'Alicia', got %q", dst.Name)
+	}
+	if dst.Email != nil {
+		t.Errorf("expected Email to be cleared, got %q", *dst.Email)
+	}
+	if dst.Age != 31 {
+		t.Errorf("expected Age = 31, got %d", dst.Age)
+	}
+	if !dst.Active {
+		t.Error("expected Active to be kept")
+	}
+	if dst.Score != 1.5 {
+		t.Errorf("expected Score = 1.5, got %v", dst.Score)
+	}
+	if dst.Address != address {
+		t.Error("expected nested object to merge into the existing pointee")
+	}
+	if dst.Address.City != "Paris" || dst.Address.Zip != "75002" {
+		t.Errorf("expected Address = {Paris 75002}, got %+v", *dst.Address)
+	}
+	if dst.Home.City != "Nice" || dst.Home.Zip != "69001" {
+		t.Errorf("expected Home = {Nice 69001}, got %+v", dst.Home)
+	}
+	if len(dst.Tags) != 1 || dst.Tags[0] != "c" {
+		t.Errorf("expected Tags = [c], got %v", dst.Tags)
+	}
+	if len(dst.Labels) != 2 || dst.Labels["team"] != "core" || dst.Labels["region"] != "eu" {
+		t.Errorf("expected Labels = map[region:eu team:core], got %v", dst.Labels)
+	}
+	if len(dst.Limits) != 1 || dst.Limits[1] != 10 {
+		t.Errorf("expected Limits = map[1:10], got %v", dst.Limits)
+	}
+	if !dst.Nickname.IsNull() {
+		t.Errorf("expected Nickname to be null, got %+v", dst.Nickname)
+	}
+	if !dst.Updated.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected Updated = 2024-05-01T10:00:00Z, got %v", dst.Updated)
+	}
+	if dst.Secret != "s3cret" {
+		t.Errorf("expected Secret = 's3cret', got %q", dst.Secret)
+	}
+}
+
+func TestApplyMergePatch_FieldNames(t *testing.T) {
+	dst := patchTarget{Name: "Alice", Secret: "s3cret"}
+
+	// Without a tag name, members match Go field names
+	if err := ApplyMergePatch(&dst, []byte(`{"Name": "Bob", "Secret": "x"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "Bob" || dst.Secret != "x" {
+		t.Errorf("expected fields to match by name, got %+v", dst)
+	}
+
+	// Fields tagged "-" are never patched
+	if err := ApplyMergePatch(&dst, []byte(`{"-": "y", "Secret": "y"}`), WithTagName("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Secret != "x" {
+		t.Errorf("expected Secret to be ignored, got %q", dst.Secret)
+	}
+}
+
+func TestApplyMergePatch_NullClearsNested(t *testing.T) {
+	dst := patchTarget{
+		Address: &patchAddressJSON{City: "Paris", Zip: "75001"},
+		Home:    patchAddressJSON{City: "Lyon", Zip: "69001"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"team": "core"},
+	}
+	if err := ApplyMergePatch(&dst, []byte(`{"address": null, "home": null, "tags": null, "labels": null}`), WithTagName("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Address != nil || dst.Home != (patchAddressJSON{}) || dst.Tags != nil || dst.Labels != nil {
+		t.Errorf("expected null to clear fields, got %+v", dst)
+	}
+}
+
+func TestApplyMergePatch_NilPointerAllocates(t *testing.T) {
+	var dst patchTarget
+	if err := ApplyMergePatch(&dst, []byte(`{"address": {"city": "Rome"}}`), WithTagName("json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Address == nil || dst.Address.City != "Rome" {
+		t.Errorf("expected address to be allocated, got %+v", dst.Address)
+	}
+}
+
+func TestApplyMergePatch_MapPointer(t *testing.T) {
+	type Target struct {
+		Labels *map[string]string
+	}
+	labels := map[string]string{"a": "1", "c": "3"}
+	dst := Target{Labels: &labels}
+
+	if err := ApplyMergePatch(&dst, []byte(`{"Labels": {"b": "2", "c": null}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"a": "1", "b": "2"}; !reflect.DeepEqual(*dst.Labels, want) {
+		t.Errorf("expected the patch to merge into the existing map, got %v", *dst.Labels)
+	}
+	if len(labels) != 2 || labels["c"] != "3" {
+		t.Errorf("expected the original map to be untouched, got %v", labels)
+	}
+}
+
+func TestApplyMergePatch_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		path  string
+		code  ErrorCode
+	}{
+		{"conversion", `{"age": "abc"}`, "Age", CodeConversionFailed},
+		{"overflow", `{"limits": {"1": 300}}`, "Limits[1]", CodeConversionFailed},
+		{"fraction", `{"age": 1.5}`, "Age", CodeConversionFailed},
+		{"map key", `{"limits": {"x": 1}}`, "Limits[x]", CodeConversionFailed},
+		{"incompatible", `{"name": 5}`, "Name", CodeIncompatibleTypes},
+		{"object into scalar", `{"age": {}}`, "Age", CodeIncompatibleTypes},
+		{"nested", `{"address": {"zip": true}}`, "Address.Zip", CodeIncompatibleTypes},
+		{"slice element", `{"tags": ["a", 1]}`, "Tags[1]", CodeIncompatibleTypes},
+		{"unmarshaler", `{"updated": "yesterday"}`, "Updated", CodeConversionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst patchTarget
+			err := ApplyMergePatch(&dst, []byte(tt.patch), WithTagName("json"))
+
+			var mappingErr *MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("expected *MappingError, got %v", err)
+			}
+			if mappingErr.FieldPath != tt.path || mappingErr.Code != tt.code {
+				t.Errorf("expected %s at %q, got %s at %q (%v)", tt.code, tt.path, mappingErr.Code, mappingErr.FieldPath, err)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch_InvalidInput(t *testing.T) {
+	var dst patchTarget
+
+	if err := ApplyMergePatch(&dst, []byte(`[1]`)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid input for a non-object patch, got %v", err)
+	}
+	if err := ApplyMergePatch(&dst, []byte(`{"name":`)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid input for malformed JSON, got %v", err)
+	}
+	if err := ApplyMergePatch(dst, []byte(`{}`)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid input for a non-pointer destination, got %v", err)
+	}
+	if err := ApplyMergePatch(nil, []byte(`{}`)); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected nil input, got %v", err)
+	}
+}
+
+func TestApplyMergePatch_Options(t *testing.T) {
+	var dst patchTarget
+	err := ApplyMergePatch(&dst, []byte(`{"age": "x", "unknown": 1, "score": "y"}`),
+		WithTagName("json"), WithStrictSource(), WithCollectErrors())
+
+	var errs MappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MappingErrors, got %v", err)
+	}
+	if got := collectedPaths(errs); !reflect.DeepEqual(got, []string{"Age", "Score", "unknown"}) {
+		t.Errorf("unexpected paths %v", got)
+	}
+	if !errors.Is(err, ErrUnmappedSource) {
+		t.Error("expected unknown member to be reported with WithStrictSource")
+	}
+
+	m := New(WithTagName("json"))
+	if err := m.ApplyMergePatch(&dst, []byte(`{"name": "Eve"}`)); err != nil || dst.Name != "Eve" {
+		t.Errorf("expected instance options to apply, got %v, %q", err, dst.Name)
+	}
+}
+
+func TestApplyMergePatch_PointerTags(t *testing.T) {
+	type Target struct {
+		Shared *patchAddressJSON
+		Copied *patchAddressJSON `mapptr:"new"`
+		Count  *int              `mapptr:"reuse"`
+	}
+	count := 1
+	shared := &patchAddressJSON{City: "Paris", Zip: "75001"}
+	copied := &patchAddressJSON{City: "Lyon", Zip: "69001"}
+	dst := Target{Shared: shared, Copied: copied, Count: &count}
+
+	err := ApplyMergePatch(&dst, []byte(`{"Shared": {"Zip": "75002"}, "Copied": {"Zip": "69002"}, "Count": 2}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Shared != shared || shared.Zip != "75002" {
+		t.Errorf("expected untagged struct pointers to be merged in place, got %+v", dst.Shared)
+	}
+	if dst.Copied == copied || copied.Zip != "69001" {
+		t.Error(`expected mapptr:"new" to leave the existing pointee untouched`)
+	}
+	if *dst.Copied != (patchAddressJSON{City: "Lyon", Zip: "69002"}) {
+		t.Errorf(`expected mapptr:"new" to merge into a copy, got %+v`, dst.Copied)
+	}
+	if dst.Count != &count || count != 2 {
+		t.Errorf(`expected mapptr:"reuse" to write into the existing pointee, got %d`, *dst.Count)
+	}
+}
+
+func TestApplyMergePatch_Converters(t *testing.T) {
+	type Target struct {
+		Created time.Time
+		Cents   int64
+		Age     int
+	}
+	toTime := WithConverter(func(s string) (time.Time, error) {
+		return time.Parse("2006-01-02", s)
+	})
+	toCents := WithConverter(func(f float64) (int64, error) {
+		return int64(f * 100), nil
+	})
+
+	var dst Target
+	if err := ApplyMergePatch(&dst, []byte(`{"Created": "2024-03-01", "Cents": 12.5, "Age": 7}`), toTime, toCents); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !dst.Created.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || dst.Cents != 1250 || dst.Age != 7 {
+		t.Errorf("expected scalars to use the converters, got %+v", dst)
+	}
+
+	err := ApplyMergePatch(&dst, []byte(`{"Created": "yesterday"}`), toTime)
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.Code != CodeConverterFailed {
+		t.Fatalf("expected a converter error, got %v", err)
+	}
+	if mappingErr.SrcType != patchSource || mappingErr.FieldPath != "Created" {
+		t.Errorf("unexpected error %+v", mappingErr)
+	}
+}
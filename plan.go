@@ -0,0 +1,316 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// fieldStrategy identifies how a matched field pair is assigned.
+// It is chosen once when a plan is compiled so the hot path does not
+// re-inspect kinds and types on every call.
+type fieldStrategy uint8
+
+const (
+	// strategySet assigns directly; both types are non-composite and assignable.
+	strategySet fieldStrategy = iota
+	// strategyConvert converts; both types are non-composite and convertible.
+	strategyConvert
+	// strategyParse parses a string source using a pre-bound mapconv parser.
+	strategyParse
+	// strategyStruct recursively maps a nested struct.
+	strategyStruct
+	// strategySlice deep copies a slice.
+	strategySlice
+	// strategyMap deep copies a map.
+	strategyMap
+	// strategyNested defers to assignNestedValue (pointers and error cases).
+	strategyNested
+	// strategyOptional unwraps an optional source; see Optional.
+	strategyOptional
+)
+
+// fieldPlan is the pre-resolved assignment of a single destination field.
+type fieldPlan struct {
+	name      string // destination field name, used for error paths
+	srcIndex  int
+	dstIndex  int
+	srcType   reflect.Type
+	dstType   reflect.Type
+	strategy  fieldStrategy
+	convertTo string
+	parse     stringParser
+	optional  *optionalInfo
+	unmatched bool // no source field matches by name or tag
+	// selfMapped is set when the types implement MapFrom or MapTo, which
+	// assignNestedValue consults before the built-in rules.
+	selfMapped bool
+
+	// merge is the strategy from the destination field's mapmerge and mapkey
+	// tags; mergeTagged reports whether the field has either tag.
+	merge       mergeSpec
+	mergeTagged bool
+	// pointers is set by the destination field's mapptr tag and applies to
+	// every pointer mapped within the field.
+	pointers pointerMode
+
+	tagError     string // malformed tag on the destination field
+	tagErrorCode ErrorCode
+}
+
+// structPlan is the compiled mapping between a source and a destination struct type.
+// Plans are immutable once built and shared between goroutines.
+type structPlan struct {
+	srcType reflect.Type
+	dstType reflect.Type
+	fields  []fieldPlan // destination fields in declaration order
+	// unconsumed lists the source fields no destination field maps from,
+	// excluding fields tagged `mapstrict:"-"`.
+	unconsumed []string
+	// copyWhole is set when both types are identical, contain no composite
+	// fields and have no hooks, so the struct can be assigned in one step.
+	copyWhole bool
+	// hooks lists the BeforeMap and AfterMap hooks the types implement.
+	hooks hookSet
+}
+
+type planKey struct {
+	src     reflect.Type
+	dst     reflect.Type
+	tagName string
+}
+
+// getStructPlan returns the cached plan for mapping srcType into dstType,
+// compiling and caching it on first use.
+func (m *Mapper) getStructPlan(srcType, dstType reflect.Type, tagName string) (*structPlan, error) {
+	key := planKey{src: srcType, dst: dstType, tagName: tagName}
+
+	if cached, ok := m.planCache.Load(key); ok {
+		return cached.(*structPlan), nil
+	}
+
+	srcMeta, err := m.getStructMeta(srcType, tagName)
+	if err != nil {
+		return nil, err
+	}
+	dstMeta, err := m.getStructMeta(dstType, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	p := compileStructPlan(srcMeta, dstMeta)
+
+	actual, _ := m.planCache.LoadOrStore(key, p)
+	return actual.(*structPlan), nil
+}
+
+func compileStructPlan(srcMeta, dstMeta *structMeta) *structPlan {
+	hooks := hooksFor(srcMeta.Type, dstMeta.Type)
+	p := &structPlan{
+		srcType:   srcMeta.Type,
+		dstType:   dstMeta.Type,
+		fields:    make([]fieldPlan, 0, len(dstMeta.Fields)),
+		copyWhole: srcMeta.Type == dstMeta.Type && !srcMeta.HasComposite && hooks == 0,
+		hooks:     hooks,
+	}
+
+	consumed := make(map[*fieldMeta]bool, len(srcMeta.Fields))
+
+	for _, dstField := range dstMeta.Fields {
+		srcField, ok := srcMeta.FieldsByName[dstField.Name]
+		if !ok {
+			srcField, ok = srcMeta.FieldsByTag[dstField.Name]
+		}
+
+		if !ok {
+			p.fields = append(p.fields, fieldPlan{
+				name:      dstField.Name,
+				dstIndex:  dstField.Index[0],
+				dstType:   dstField.Type,
+				unmatched: true,
+			})
+			continue
+		}
+
+		consumed[srcField] = true
+		fp := compileFieldPlan(srcField, dstField)
+		if fp.selfMapped {
+			p.copyWhole = false
+		}
+		p.fields = append(p.fields, fp)
+	}
+
+	for _, srcField := range srcMeta.Fields {
+		if !consumed[srcField] && !srcField.NoStrictSource {
+			p.unconsumed = append(p.unconsumed, srcField.Name)
+		}
+	}
+
+	return p
+}
+
+func compileFieldPlan(srcField, dstField *fieldMeta) fieldPlan {
+	fp := fieldPlan{
+		name:      dstField.Name,
+		srcIndex:  srcField.Index[0],
+		dstIndex:  dstField.Index[0],
+		srcType:   srcField.Type,
+		dstType:   dstField.Type,
+		convertTo: srcField.ConvertTo,
+
+		merge:        dstField.Merge,
+		mergeTagged:  dstField.MergeTagged,
+		pointers:     dstField.Pointers,
+		tagError:     dstField.TagError,
+		tagErrorCode: dstField.TagErrorCode,
+	}
+
+	sType := srcField.Type
+	dType := dstField.Type
+	srcKind := sType.Kind()
+	dstKind := dType.Kind()
+
+	// Optionals are unwrapped, or copied as they are into the same type;
+	// their fields are unexported and cannot be mapped one by one.
+	if info := optionalOf(sType); info != nil {
+		if sType.AssignableTo(dType) {
+			fp.strategy = strategySet
+		} else {
+			fp.strategy = strategyOptional
+			fp.optional = info
+		}
+		return fp
+	}
+
+	if fp.convertTo != "" && srcKind == reflect.String {
+		if parse, ok := stringParsers[fp.convertTo]; ok {
+			fp.strategy = strategyParse
+			fp.parse = parse
+		} else {
+			// Unsupported target types are reported by assignNestedValue.
+			fp.strategy = strategyNested
+		}
+		return fp
+	}
+
+	if selfMapping(sType, dType) {
+		fp.selfMapped = true
+		fp.strategy = strategyNested
+		return fp
+	}
+
+	switch {
+	case !isCompositeKind(srcKind) && !isCompositeKind(dstKind):
+		if sType.AssignableTo(dType) {
+			fp.strategy = strategySet
+		} else if sType.ConvertibleTo(dType) {
+			fp.strategy = strategyConvert
+		} else {
+			fp.strategy = strategyNested
+		}
+	case srcKind == reflect.Struct && dstKind == reflect.Struct:
+		fp.strategy = strategyStruct
+	case srcKind == reflect.Slice && dstKind == reflect.Slice:
+		fp.strategy = strategySlice
+	case srcKind == reflect.Map && dstKind == reflect.Map:
+		fp.strategy = strategyMap
+	default:
+		fp.strategy = strategyNested
+	}
+
+	return fp
+}
+
+// isCompositeKind reports whether values of kind k require recursive mapping.
+// Interfaces are composite because their dynamic value may be.
+func isCompositeKind(k reflect.Kind) bool {
+	return k == reflect.Struct || k == reflect.Slice || k == reflect.Map || k == reflect.Ptr || k == reflect.Interface
+}
+
+// assignField assigns a single field pair using the strategy chosen at compile time.
+// basePath is the path of the enclosing struct; the field path is only built
+// when recursion or an error requires it.
+func assignField(fp *fieldPlan, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, cfg *config, depth int) error {
+	// Converters are looked up at call time because they can be supplied per call.
+	// Explicit mapconv tags on string fields take precedence.
+	if cfg.hasConverters() && (fp.convertTo == "" || fp.srcType.Kind() != reflect.String) {
+		if conv, ok := cfg.lookupConverter(fp.srcType, fp.dstType); ok {
+			return applyConverter(conv, dst, src, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg)
+		}
+	}
+
+	if fp.tagError != "" {
+		return &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: buildPath(basePath, fp.name),
+			Reason:    fp.tagError,
+			Code:      fp.tagErrorCode,
+		}
+	}
+
+	// The mapptr tag overrides the pointer mode for everything below the field
+	if fp.pointers != pointerDefault && fp.pointers != cfg.pointers {
+		saved := cfg.pointers
+		cfg.pointers = fp.pointers
+		err := assignFieldValue(fp, dst, src, srcStructType, dstStructType, basePath, cfg, depth)
+		cfg.pointers = saved
+		return err
+	}
+
+	return assignFieldValue(fp, dst, src, srcStructType, dstStructType, basePath, cfg, depth)
+}
+
+// assignFieldValue applies the compiled strategy of fp.
+func assignFieldValue(fp *fieldPlan, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, cfg *config, depth int) error {
+	switch fp.strategy {
+	case strategySet:
+		dst.Set(src)
+		return nil
+
+	case strategyConvert:
+		dst.Set(src.Convert(fp.dstType))
+		return nil
+
+	case strategyParse:
+		str := src.String()
+		converted, err := fp.parse(str)
+		if err != nil {
+			return conversionError(str, fp.convertTo, err, srcStructType, dstStructType, buildPath(basePath, fp.name))
+		}
+		dst.Set(converted.Convert(fp.dstType))
+		return nil
+
+	case strategyStruct:
+		return assignStruct(dst, src, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth-1)
+
+	case strategySlice:
+		if spec, ok := cfg.mergeFor(fp); ok {
+			return mergeSlice(dst, src, spec, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth-1)
+		}
+		return assignSlice(dst, src, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth-1)
+
+	case strategyOptional:
+		if spec, ok := cfg.mergeFor(fp); ok {
+			if value, present, set := readOptional(src, fp.optional); present && set {
+				if merged, err := mergeValue(dst, value, spec, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth); merged {
+					return err
+				}
+			}
+		}
+		return assignOptional(dst, src, fp.optional, srcStructType, dstStructType, basePath, fp.name, fp.convertTo, cfg, depth)
+
+	case strategyMap:
+		if spec, ok := cfg.mergeFor(fp); ok {
+			return assignMapMerge(dst, src, spec, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth-1)
+		}
+		return assignMap(dst, src, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth-1)
+
+	default:
+		// Collections behind pointers are merged like plain slice and map fields
+		if spec, ok := cfg.mergeFor(fp); ok && !fp.selfMapped {
+			if merged, err := mergeValue(dst, src, spec, srcStructType, dstStructType, buildPath(basePath, fp.name), cfg, depth); merged {
+				return err
+			}
+		}
+		return assignNestedValue(dst, src, srcStructType, dstStructType, basePath, fp.name, fp.convertTo, cfg, depth)
+	}
+}
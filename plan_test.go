@@ -0,0 +1,222 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+type planSrc struct {
+	Name    string
+	Age     int32
+	Score   string `mapconv:"float64"`
+	Alias   string `map:"Nick"`
+	Address SrcAddress
+	Tags    []string
+	Meta    map[string]string
+	Ref     *string
+}
+
+type planDst struct {
+	Name    string
+	Age     int64
+	Score   float64
+	Nick    string
+	Address DstAddress
+	Tags    []string
+	Meta    map[string]string
+	Ref     *string
+	Missing string
+}
+
+func TestPlan_CachedPerTypePair(t *testing.T) {
+	srcType := reflect.TypeOf(planSrc{})
+	dstType := reflect.TypeOf(planDst{})
+	m := New()
+
+	p1, err := m.getStructPlan(srcType, dstType, "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p2, err := m.getStructPlan(srcType, dstType, "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p1 != p2 {
+		t.Error("expected the same plan instance for the same type pair and tag name")
+	}
+
+	p3, err := m.getStructPlan(srcType, dstType, "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p1 == p3 {
+		t.Error("expected a different plan for a different tag name")
+	}
+}
+
+func TestPlan_Strategies(t *testing.T) {
+	p, err := defaultMapper.getStructPlan(reflect.TypeOf(planSrc{}), reflect.TypeOf(planDst{}), "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]fieldStrategy{
+		"Name":    strategySet,
+		"Age":     strategyConvert,
+		"Score":   strategyParse,
+		"Nick":    strategySet,
+		"Address": strategyStruct,
+		"Tags":    strategySlice,
+		"Meta":    strategyMap,
+		"Ref":     strategyNested,
+	}
+
+	if len(p.fields) != 9 {
+		t.Fatalf("expected 9 field plans, got %d", len(p.fields))
+	}
+
+	for _, fp := range p.fields {
+		if fp.name == "Missing" {
+			if !fp.unmatched {
+				t.Error("expected Missing to be unmatched")
+			}
+			continue
+		}
+		if fp.unmatched {
+			t.Errorf("expected %s to be matched", fp.name)
+			continue
+		}
+		if want := expected[fp.name]; fp.strategy != want {
+			t.Errorf("field %s: expected strategy %d, got %d", fp.name, want, fp.strategy)
+		}
+	}
+}
+
+func TestPlan_CopyWhole(t *testing.T) {
+	p, err := defaultMapper.getStructPlan(reflect.TypeOf(SrcAddress{}), reflect.TypeOf(SrcAddress{}), "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.copyWhole {
+		t.Error("expected copyWhole for identical struct types without composite fields")
+	}
+
+	p, err = defaultMapper.getStructPlan(reflect.TypeOf(SrcPerson{}), reflect.TypeOf(SrcPerson{}), "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.copyWhole {
+		t.Error("expected no copyWhole for struct types with composite fields")
+	}
+}
+
+func TestPlan_UnsupportedMapconvFallsBack(t *testing.T) {
+	type Src struct {
+		Value string `mapconv:"complex128"`
+	}
+	type Dst struct {
+		Value complex128
+	}
+
+	p, err := defaultMapper.getStructPlan(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}), "map")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.fields[0].strategy != strategyNested {
+		t.Errorf("expected strategyNested for unsupported mapconv target, got %d", p.fields[0].strategy)
+	}
+}
+
+func TestPlan_MapsAllStrategies(t *testing.T) {
+	ref := "ref"
+	src := planSrc{
+		Name:    "Alice",
+		Age:     30,
+		Score:   "9.5",
+		Alias:   "ally",
+		Address: SrcAddress{City: "Seattle"},
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]string{"k": "v"},
+		Ref:     &ref,
+	}
+	var dst planDst
+
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "Alice" || dst.Age != 30 || dst.Score != 9.5 || dst.Nick != "ally" {
+		t.Errorf("unexpected scalar fields: %+v", dst)
+	}
+	if dst.Address.City != "Seattle" {
+		t.Errorf("expected Address.City = 'Seattle', got %q", dst.Address.City)
+	}
+	if len(dst.Tags) != 2 || dst.Meta["k"] != "v" {
+		t.Errorf("unexpected collections: %v %v", dst.Tags, dst.Meta)
+	}
+	if dst.Ref == nil || *dst.Ref != "ref" || dst.Ref == src.Ref {
+		t.Error("expected Ref to be deep copied")
+	}
+}
+
+func TestPlan_ParseErrorPathInNestedStruct(t *testing.T) {
+	type SrcInner struct {
+		Age string `mapconv:"int"`
+	}
+	type DstInner struct {
+		Age int
+	}
+	type Src struct {
+		Inner *SrcInner
+	}
+	type Dst struct {
+		Inner *DstInner
+	}
+
+	src := Src{Inner: &SrcInner{Age: "abc"}}
+	var dst Dst
+
+	err := Map(&dst, src)
+	if err == nil {
+		t.Fatal("expected conversion error, got nil")
+	}
+
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T", err)
+	}
+	if mappingErr.FieldPath != "Inner.Age" {
+		t.Errorf("expected FieldPath = 'Inner.Age', got %q", mappingErr.FieldPath)
+	}
+}
+
+func TestPlan_SliceOfStructsErrorPath(t *testing.T) {
+	type SrcItem struct {
+		Qty string `mapconv:"int"`
+	}
+	type DstItem struct {
+		Qty int
+	}
+	type Src struct {
+		Items []SrcItem
+	}
+	type Dst struct {
+		Items []DstItem
+	}
+
+	src := Src{Items: []SrcItem{{Qty: "1"}, {Qty: "x"}}}
+	var dst Dst
+
+	err := Map(&dst, src)
+	if err == nil {
+		t.Fatal("expected conversion error, got nil")
+	}
+
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T", err)
+	}
+	if mappingErr.FieldPath != "Items[1].Qty" {
+		t.Errorf("expected FieldPath = 'Items[1].Qty', got %q", mappingErr.FieldPath)
+	}
+}
@@ -0,0 +1,72 @@
+package mapper
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// registry holds the entries added with one of the Register functions, such
+// as [RegisterConverter]. It is safe for concurrent use; count keeps lookups
+// off the hot path while the registry is empty.
+type registry[K comparable, V any] struct {
+	entries sync.Map // map[K]V
+	count   atomic.Int32
+}
+
+// store adds or replaces the entry for k.
+func (r *registry[K, V]) store(k K, v V) {
+	if _, loaded := r.entries.Swap(k, v); !loaded {
+		r.count.Add(1)
+	}
+}
+
+// delete removes the entry for k, if any.
+func (r *registry[K, V]) delete(k K) {
+	if _, loaded := r.entries.LoadAndDelete(k); loaded {
+		r.count.Add(-1)
+	}
+}
+
+// load returns the entry for k.
+func (r *registry[K, V]) load(k K) (V, bool) {
+	if r.count.Load() == 0 {
+		var zero V
+		return zero, false
+	}
+	v, ok := r.entries.Load(k)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return v.(V), true
+}
+
+// hasEntries reports whether a lookup may find an entry in the per-call map
+// local or, for calls that consult registered entries, in r.
+func hasEntries[K comparable, V any](local map[K]V, r *registry[K, V], registered bool) bool {
+	return len(local) > 0 || (registered && r.count.Load() > 0)
+}
+
+// lookupEntry returns the entry for k, preferring the per-call map local over
+// the registry r, which is only consulted for calls with registered set.
+func lookupEntry[K comparable, V any](local map[K]V, r *registry[K, V], registered bool, k K) (V, bool) {
+	if v, ok := local[k]; ok {
+		return v, true
+	}
+	if !registered {
+		var zero V
+		return zero, false
+	}
+	return r.load(k)
+}
+
+// copyMap returns a copy of m with room for extra more entries. Options add
+// to the copy, so they never mutate a map shared with another call or with
+// the Mapper they were passed to.
+func copyMap[K comparable, V any](m map[K]V, extra int) map[K]V {
+	out := make(map[K]V, len(m)+extra)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
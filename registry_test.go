@@ -0,0 +1,67 @@
+package mapper
+
+import "testing"
+
+func TestRegistry_StoreLoadDelete(t *testing.T) {
+	var r registry[string, int]
+
+	if _, ok := r.load("a"); ok {
+		t.Fatal("expected empty registry to miss")
+	}
+
+	r.store("a", 1)
+	r.store("a", 2)
+	if got := r.count.Load(); got != 1 {
+		t.Errorf("expected count 1 after replacing an entry, got %d", got)
+	}
+	if v, ok := r.load("a"); !ok || v != 2 {
+		t.Errorf("expected 2, true; got %d, %v", v, ok)
+	}
+
+	r.delete("a")
+	r.delete("a")
+	if got := r.count.Load(); got != 0 {
+		t.Errorf("expected count 0 after delete, got %d", got)
+	}
+	if _, ok := r.load("a"); ok {
+		t.Error("expected deleted entry to miss")
+	}
+}
+
+func TestLookupEntry_PrefersLocalAndHonorsRegistered(t *testing.T) {
+	var r registry[string, int]
+	r.store("shared", 1)
+	r.store("global", 2)
+	local := map[string]int{"shared": 10}
+
+	if v, _ := lookupEntry(local, &r, true, "shared"); v != 10 {
+		t.Errorf("expected per-call entry 10, got %d", v)
+	}
+	if v, ok := lookupEntry(local, &r, true, "global"); !ok || v != 2 {
+		t.Errorf("expected registered entry 2, true; got %d, %v", v, ok)
+	}
+	if _, ok := lookupEntry(local, &r, false, "global"); ok {
+		t.Error("expected registered entry to be ignored when registered is false")
+	}
+
+	if !hasEntries(nil, &r, true) {
+		t.Error("expected hasEntries to see registered entries")
+	}
+	if hasEntries(nil, &r, false) {
+		t.Error("expected hasEntries to ignore registered entries when registered is false")
+	}
+}
+
+func TestCopyMap_LeavesOriginal(t *testing.T) {
+	orig := map[string]int{"a": 1}
+
+	cp := copyMap(orig, 1)
+	cp["b"] = 2
+
+	if len(orig) != 1 {
+		t.Errorf("expected original to keep 1 entry, got %d", len(orig))
+	}
+	if cp["a"] != 1 || cp["b"] != 2 {
+		t.Errorf("expected copy {a:1 b:2}, got %v", cp)
+	}
+}
@@ -0,0 +1,204 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// FieldStatus describes what a mapping call did with a destination field.
+type FieldStatus uint8
+
+const (
+	// FieldWritten reports a field that was assigned from its source field.
+	// The value may be unchanged; see [FieldReport.Changed].
+	FieldWritten FieldStatus = iota
+	// FieldSkippedZero reports a field left unchanged because its source
+	// field was zero and [WithIgnoreZeroSource] was set.
+	FieldSkippedZero
+	// FieldUnmatched reports a field left unchanged because no source field
+	// matches it by name or tag.
+	FieldUnmatched
+)
+
+// String returns "written", "skipped_zero" or "unmatched".
+func (s FieldStatus) String() string {
+	switch s {
+	case FieldSkippedZero:
+		return "skipped_zero"
+	case FieldUnmatched:
+		return "unmatched"
+	default:
+		return "written"
+	}
+}
+
+// FieldReport describes one destination field in a [Report].
+type FieldReport struct {
+	// Path is the destination field path, such as "Address.City".
+	Path   string
+	Status FieldStatus
+	// Old is the value before the call and New the value after it; New is
+	// nil for skipped fields.
+	Old any
+	New any
+	// Changed is set for written fields whose new value differs from the
+	// old one.
+	Changed bool
+}
+
+// Report lists the destination fields of a mapping call, in the order they
+// were visited. Fields of nested structs are reported individually; other
+// fields, including slices, maps and pointers, are reported as a whole.
+type Report struct {
+	Fields []FieldReport
+}
+
+// Changed returns the written fields whose value changed.
+func (r *Report) Changed() []FieldReport {
+	var changed []FieldReport
+	for _, f := range r.Fields {
+		if f.Changed {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// HasChanges reports whether any destination field changed.
+func (r *Report) HasChanges() bool {
+	for _, f := range r.Fields {
+		if f.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// WithChangeReport fills r with the destination fields of the mapping call:
+// the fields that were written, with their old and new values, and the
+// fields that were skipped because their source was zero or had no match.
+// r is reset at the start of each call.
+//
+// Example:
+//
+//	var report mapper.Report
+//	err := mapper.MapWithOptions(&user, req,
+//	    mapper.WithIgnoreZeroSource(),
+//	    mapper.WithChangeReport(&report),
+//	)
+//	for _, f := range report.Changed() {
+//	    audit.Log(f.Path, f.Old, f.New)
+//	}
+//	if !report.HasChanges() {
+//	    return nil // skip the database write
+//	}
+//
+// Old values are deep copies taken before each field is written, so they
+// are not affected by merge strategies or pointer reuse. A field whose old
+// value is nested more deeply than [WithMaxDepth] allows is left unchanged
+// and fails with [CodeDepthExceeded]. The report is
+// filled by [Map], [MapWithOptions] and the [Mapper] methods; a report
+// passed to [New] is shared by every call and must not be used concurrently.
+// Generated mappers are bypassed while a report is requested.
+func WithChangeReport(r *Report) Option {
+	return func(c *config) {
+		c.report = r
+	}
+}
+
+// DryRun maps src into a copy of dst and returns the [Report] of the fields
+// the call would write or skip, without modifying dst. Mapping errors are
+// returned as they would be by [MapWithOptions], along with the report of
+// the fields visited before them. If dst is nested more deeply than
+// [WithMaxDepth] allows, it cannot be copied and DryRun returns a
+// [CodeDepthExceeded] error with an empty report.
+//
+// Example:
+//
+//	report, err := mapper.DryRun(&user, req, mapper.WithIgnoreZeroSource())
+//	if err == nil && report.HasChanges() {
+//	    // ask for confirmation, then map for real
+//	}
+func DryRun(dst, src any, opts ...Option) (*Report, error) {
+	return defaultMapper.DryRun(dst, src, opts...)
+}
+
+// DryRun reports what mapping src into dst would change using the options
+// the Mapper was created with, followed by opts. See [DryRun].
+func (m *Mapper) DryRun(dst, src any, opts ...Option) (*Report, error) {
+	cfg := m.callConfig(opts)
+	report := &Report{}
+	cfg.report = report
+
+	if v := reflect.ValueOf(dst); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		copied, err := cloneValue(v.Elem(), cfg.maxDepth)
+		if err != nil {
+			return report, err
+		}
+		clone := reflect.New(v.Elem().Type())
+		clone.Elem().Set(copied)
+		dst = clone.Interface()
+	}
+
+	return report, runMapping(dst, src, &cfg)
+}
+
+// reportAssign assigns fp like assignField and records the outcome in the
+// call's report. Nested structs record their own fields instead.
+func reportAssign(fp *fieldPlan, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, cfg *config, depth int) error {
+	if fp.strategy == strategyStruct && fp.tagError == "" && !hasConverterFor(fp, cfg) {
+		return assignField(fp, dst, src, srcStructType, dstStructType, basePath, cfg, depth)
+	}
+
+	// Structs inside collections and pointers are part of the field's value,
+	// so the report is detached while the field is assigned
+	report := cfg.report
+	old, err := cloneValue(dst, cfg.maxDepth)
+	if err != nil {
+		e := err.(*MappingError)
+		e.SrcType = srcStructType.String()
+		e.DstType = dstStructType.String()
+		e.FieldPath = joinPath(buildPath(basePath, fp.name), e.FieldPath)
+		return e
+	}
+	cfg.report = nil
+	err = assignField(fp, dst, src, srcStructType, dstStructType, basePath, cfg, depth)
+	cfg.report = report
+	if err != nil {
+		return err
+	}
+	report.Fields = append(report.Fields, FieldReport{
+		Path:    buildPath(basePath, fp.name),
+		Status:  FieldWritten,
+		Old:     old.Interface(),
+		New:     dst.Interface(),
+		Changed: !valuesEqual(old, dst),
+	})
+	return nil
+}
+
+func hasConverterFor(fp *fieldPlan, cfg *config) bool {
+	if !cfg.hasConverters() {
+		return false
+	}
+	_, ok := cfg.lookupConverter(fp.srcType, fp.dstType)
+	return ok
+}
+
+// reportSkipped records a destination field left unchanged.
+func reportSkipped(status FieldStatus, fp *fieldPlan, dst reflect.Value, basePath string, cfg *config) {
+	cfg.report.Fields = append(cfg.report.Fields, FieldReport{
+		Path:   buildPath(basePath, fp.name),
+		Status: status,
+		Old:    dst.Interface(),
+	})
+}
+
+// cloneValue returns a deep copy of v for snapshots, such as the old values
+// of a change report. Shared pointers are copied once so cyclic values are
+// supported; values nested more deeply than depth fail with a
+// [*MappingError] whose path is relative to v. Converters are not consulted,
+// so snapshots never run user code.
+func cloneValue(v reflect.Value, depth int) (reflect.Value, error) {
+	snapshot := config{maxDepth: depth, preserveRefs: true}
+	return cloneDeep(v, v.Type(), &snapshot, depth)
+}
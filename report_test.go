@@ -0,0 +1,252 @@
+package mapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type reportAddress struct {
+	City string
+	Zip  string
+}
+
+type reportUser struct {
+	Name    string
+	Age     int
+	Email   *string
+	Home    reportAddress
+	Tags    []string
+	Version int
+}
+
+type reportRequest struct {
+	Name  string
+	Age   int
+	Email *string
+	Home  reportAddress
+	Tags  []string
+}
+
+func TestWithChangeReport(t *testing.T) {
+	oldEmail := "alice@example.com"
+	dst := reportUser{
+		Name:    "Alice",
+		Age:     30,
+		Email:   &oldEmail,
+		Home:    reportAddress{City: "Lyon", Zip: "69001"},
+		Tags:    []string{"a"},
+		Version: 3,
+	}
+	email := "new@example.com"
+	req := reportRequest{Name: "Alice", Age: 31, Email: &email, Home: reportAddress{City: "Nice"}, Tags: []string{"a", "b"}}
+
+	var report Report
+	if err := MapWithOptions(&dst, req, WithIgnoreZeroSource(), WithChangeReport(&report)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []FieldReport{
+		{Path: "Name", Status: FieldWritten, Old: "Alice", New: "Alice"},
+		{Path: "Age", Status: FieldWritten, Old: 30, New: 31, Changed: true},
+		{Path: "Email", Status: FieldWritten, Old: &oldEmail, New: dst.Email, Changed: true},
+		{Path: "Home.City", Status: FieldWritten, Old: "Lyon", New: "Nice", Changed: true},
+		{Path: "Home.Zip", Status: FieldSkippedZero, Old: "69001"},
+		{Path: "Tags", Status: FieldWritten, Old: []string{"a"}, New: []string{"a", "b"}, Changed: true},
+		{Path: "Version", Status: FieldUnmatched, Old: 3},
+	}
+	if !reflect.DeepEqual(report.Fields, want) {
+		t.Errorf("expected %+v, got %+v", want, report.Fields)
+	}
+	if !report.HasChanges() || len(report.Changed()) != 4 {
+		t.Errorf("expected 4 changed fields, got %+v", report.Changed())
+	}
+
+	// The report is reset by every call
+	if err := MapWithOptions(&dst, req, WithIgnoreZeroSource(), WithChangeReport(&report)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.HasChanges() || len(report.Fields) != len(want) {
+		t.Errorf("expected an unchanged report of %d fields, got %+v", len(want), report.Fields)
+	}
+}
+
+func TestWithChangeReport_OldValuesAreCopies(t *testing.T) {
+	type Item struct {
+		ID  int
+		Qty int
+	}
+	type Order struct {
+		Items []Item `mapmerge:"merge" mapkey:"ID"`
+	}
+
+	dst := Order{Items: []Item{{ID: 1, Qty: 1}}}
+	var report Report
+	if err := MapWithOptions(&dst, Order{Items: []Item{{ID: 1, Qty: 5}}}, WithChangeReport(&report)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := report.Fields[0]
+	if old := f.Old.([]Item); old[0].Qty != 1 || !f.Changed {
+		t.Errorf("expected old value to be kept despite the in-place merge, got %+v", f)
+	}
+}
+
+func TestWithChangeReport_Mapper(t *testing.T) {
+	var report Report
+	m := New(WithChangeReport(&report))
+
+	dst := reportUser{Name: "Alice", Age: 30}
+	if err := m.Map(&dst, reportRequest{Name: "Bob"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed := report.Changed(); len(changed) == 0 || changed[0].Path != "Name" {
+		t.Errorf("expected Name to be reported as changed, got %+v", changed)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dst := reportUser{
+		Name: "Alice",
+		Age:  30,
+		Home: reportAddress{City: "Lyon", Zip: "69001"},
+		Tags: []string{"a"},
+	}
+	req := reportRequest{Name: "Bob", Home: reportAddress{City: "Nice"}, Tags: []string{"x"}}
+
+	report, err := DryRun(&dst, req, WithIgnoreZeroSource())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "Alice" {
+		t.Errorf("expected Name = 'Alice', got %q", dst.Name)
+	}
+	if dst.Home.City != "Lyon" {
+		t.Errorf("expected Home.City = 'Lyon', got %q", dst.Home.City)
+	}
+	if len(dst.Tags) != 1 || dst.Tags[0] != "a" {
+		t.Errorf("expected Tags = [a], got %v", dst.Tags)
+	}
+
+	var paths []string
+	for _, f := range report.Changed() {
+		paths = append(paths, f.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{"Name", "Home.City", "Tags"}) {
+		t.Errorf("unexpected changed paths %v", paths)
+	}
+}
+
+func TestDryRun_PointerReuse(t *testing.T) {
+	type Profile struct{ Bio string }
+	type Account struct{ Profile *Profile }
+
+	dst := Account{Profile: &Profile{Bio: "old"}}
+	profile := dst.Profile
+	report, err := New().DryRun(&dst, Account{Profile: &Profile{Bio: "new"}}, WithReusePointers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Profile != profile || profile.Bio != "old" {
+		t.Errorf("expected the existing pointee to be untouched, got %+v", dst.Profile)
+	}
+	if !report.HasChanges() {
+		t.Error("expected Profile to be reported as changed")
+	}
+}
+
+func TestDryRun_Errors(t *testing.T) {
+	type BadRequest struct {
+		Name string
+		Age  string `mapconv:"int"`
+	}
+
+	dst := reportUser{Name: "Alice", Age: 30}
+	report, err := DryRun(&dst, BadRequest{Name: "Bob", Age: "x"})
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.FieldPath != "Age" {
+		t.Errorf("expected conversion error at Age, got %v", err)
+	}
+	if len(report.Fields) != 1 || report.Fields[0].Path != "Name" {
+		t.Errorf("expected fields visited before the error, got %+v", report.Fields)
+	}
+
+	if _, err := DryRun(dst, BadRequest{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected invalid input for a non-pointer destination, got %v", err)
+	}
+}
+
+func TestWithChangeReport_DeepOldValue(t *testing.T) {
+	type Node struct {
+		Next *Node
+	}
+	type Src struct {
+		Name  string
+		Chain *Node
+	}
+	type Dst struct {
+		Name  string
+		Chain *Node
+	}
+
+	chain := &Node{Next: &Node{Next: &Node{Next: &Node{}}}}
+	dst := Dst{Name: "Alice", Chain: chain}
+	var report Report
+	err := MapWithOptions(&dst, Src{Name: "Bob"}, WithMaxDepth(3), WithChangeReport(&report))
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		t.Fatalf("expected *MappingError, got %v", err)
+	}
+	if mappingErr.Code != CodeDepthExceeded {
+		t.Errorf("expected CodeDepthExceeded, got %v", mappingErr.Code)
+	}
+	if mappingErr.FieldPath != "Chain.Next" {
+		t.Errorf("expected FieldPath Chain.Next, got %q", mappingErr.FieldPath)
+	}
+	if dst.Chain != chain {
+		t.Error("expected Chain to be left unchanged when its old value cannot be copied")
+	}
+	if len(report.Fields) != 1 || report.Fields[0].Path != "Name" {
+		t.Errorf("expected only Name in the report, got %+v", report.Fields)
+	}
+
+	if _, err := DryRun(&dst, Src{Name: "Carol"}, WithMaxDepth(3)); !errors.Is(err, ErrDepthExceeded) {
+		t.Errorf("expected DryRun to fail copying the destination, got %v", err)
+	}
+}
+
+func TestDryRun_SnapshotsSkipConverters(t *testing.T) {
+	type Note struct{ Text string }
+	type Src struct{ Name string }
+	type Dst struct {
+		Name  string
+		Notes []Note
+	}
+
+	calls := 0
+	registerTestConverter(t, func(n Note) (Note, error) {
+		calls++
+		return n, nil
+	})
+
+	dst := Dst{Notes: []Note{{Text: "a"}}}
+	if _, err := DryRun(&dst, Src{Name: "Bob"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report Report
+	if err := MapWithOptions(&dst, Src{Name: "Bob"}, WithChangeReport(&report)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected snapshots not to call registered converters, got %d calls", calls)
+	}
+}
+
+func TestFieldStatus_String(t *testing.T) {
+	for status, want := range map[FieldStatus]string{FieldWritten: "written", FieldSkippedZero: "skipped_zero", FieldUnmatched: "unmatched"} {
+		if got := status.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// MapFromer is implemented by destination types that map themselves, such as
+// value objects with invariants or types with unexported state.
+//
+// Wherever a value is assigned to a destination of the implementing type,
+// including nested fields, slice elements, map values and pointer elements,
+// MapFrom is called with the source value before the built-in rules. It
+// reports whether it handled the value; when it did not, mapping continues
+// with [MapToer] and the built-in rules. The rest of the tree is still mapped
+// automatically.
+//
+// Example:
+//
+//	func (m *Money) MapFrom(src any) (bool, error) {
+//	    s, ok := src.(string)
+//	    if !ok {
+//	        return false, nil
+//	    }
+//	    parsed, err := ParseMoney(s)
+//	    if err != nil {
+//	        return true, err
+//	    }
+//	    *m = parsed
+//	    return true, nil
+//	}
+//
+// An error is returned as a [*MappingError] with code [CodeConverterFailed]
+// and the path of the value. The method may have a pointer receiver.
+type MapFromer interface {
+	MapFrom(src any) (handled bool, err error)
+}
+
+// MapToer is implemented by source types that map themselves into their
+// destinations. MapTo is called with a pointer to the destination value when
+// the destination has no [MapFromer] or did not handle the value, and reports
+// whether it did.
+type MapToer interface {
+	MapTo(dst any) (handled bool, err error)
+}
+
+// MapFromerContext is like [MapFromer] for a method that also receives the
+// context of the mapping call: the ctx passed to [MapContext], or
+// context.Background for calls made without one. It is called instead of
+// MapFrom when a type implements both.
+type MapFromerContext interface {
+	MapFromContext(ctx context.Context, src any) (handled bool, err error)
+}
+
+// MapToerContext is like [MapToer] for a method that also receives the
+// context of the mapping call, and is called instead of MapTo.
+type MapToerContext interface {
+	MapToContext(ctx context.Context, dst any) (handled bool, err error)
+}
+
+var (
+	mapFromerType        = typeFor[MapFromer]()
+	mapToerType          = typeFor[MapToer]()
+	mapFromerContextType = typeFor[MapFromerContext]()
+	mapToerContextType   = typeFor[MapToerContext]()
+)
+
+// selfMapInfo records which self-mapping methods, plain or context-aware, a
+// type has through a pointer receiver.
+type selfMapInfo struct {
+	mapFrom bool
+	mapTo   bool
+}
+
+// selfMapTypes caches selfMapOf results, since the method sets are checked
+// for every value assigned.
+var selfMapTypes sync.Map // map[reflect.Type]selfMapInfo
+
+// selfMapOf returns the self-mapping methods of t.
+func selfMapOf(t reflect.Type) selfMapInfo {
+	// Pointers to pointers and interfaces have no methods, and neither do
+	// predeclared types or unnamed types other than structs, which keeps the
+	// common cases off the cache
+	switch kind := t.Kind(); {
+	case kind == reflect.Ptr || kind == reflect.Interface:
+		return selfMapInfo{}
+	case kind != reflect.Struct && t.PkgPath() == "":
+		return selfMapInfo{}
+	}
+	if cached, ok := selfMapTypes.Load(t); ok {
+		return cached.(selfMapInfo)
+	}
+	ptr := reflect.PointerTo(t)
+	info := selfMapInfo{
+		mapFrom: ptr.Implements(mapFromerType) || ptr.Implements(mapFromerContextType),
+		mapTo:   ptr.Implements(mapToerType) || ptr.Implements(mapToerContextType),
+	}
+	selfMapTypes.Store(t, info)
+	return info
+}
+
+// selfMapping reports whether assigning values of sType to dType consults
+// MapFrom or MapTo.
+func selfMapping(sType, dType reflect.Type) bool {
+	return selfMapOf(dType).mapFrom || selfMapOf(sType).mapTo
+}
+
+// assignSelf calls the MapFrom method of dst and then the MapTo method of src,
+// if their types implement them, until one handles the value. Context-aware
+// variants receive ctx. It reports whether the value was handled.
+func assignSelf(ctx context.Context, dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string) (bool, error) {
+	if selfMapOf(dst.Type()).mapFrom {
+		var handled bool
+		var err error
+		method := "MapFrom"
+		switch recv := dst.Addr().Interface().(type) {
+		case MapFromerContext:
+			method = "MapFromContext"
+			handled, err = recv.MapFromContext(ctx, src.Interface())
+		case MapFromer:
+			handled, err = recv.MapFrom(src.Interface())
+		}
+		if err != nil {
+			return true, selfMapError(method, err, srcStructType, dstStructType, fieldPath)
+		}
+		if handled {
+			return true, nil
+		}
+	}
+	if selfMapOf(src.Type()).mapTo {
+		var handled bool
+		var err error
+		method := "MapTo"
+		switch recv := hookReceiver(src).(type) {
+		case MapToerContext:
+			method = "MapToContext"
+			handled, err = recv.MapToContext(ctx, dst.Addr().Interface())
+		case MapToer:
+			handled, err = recv.MapTo(dst.Addr().Interface())
+		}
+		if err != nil {
+			return true, selfMapError(method, err, srcStructType, dstStructType, fieldPath)
+		}
+		return handled, nil
+	}
+	return false, nil
+}
+
+func selfMapError(method string, err error, srcStructType, dstStructType reflect.Type, fieldPath string) error {
+	return &MappingError{
+		SrcType:   srcStructType.String(),
+		DstType:   dstStructType.String(),
+		FieldPath: fieldPath,
+		Reason:    method + " failed: " + err.Error(),
+		Code:      CodeConverterFailed,
+		Err:       err,
+	}
+}
@@ -0,0 +1,235 @@
+package mapper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// selfEmail normalizes and validates itself; its state is unexported.
+type selfEmail struct {
+	addr string
+}
+
+func (e *selfEmail) MapFrom(src any) (bool, error) {
+	s, ok := src.(string)
+	if !ok {
+		return false, nil
+	}
+	if !strings.Contains(s, "@") {
+		return true, errors.New("invalid email")
+	}
+	e.addr = strings.ToLower(s)
+	return true, nil
+}
+
+// selfCents maps itself into strings and leaves other destinations to the engine.
+type selfCents int64
+
+func (c selfCents) MapTo(dst any) (bool, error) {
+	s, ok := dst.(*string)
+	if !ok {
+		return false, nil
+	}
+	*s = strconv.FormatFloat(float64(c)/100, 'f', -1, 64)
+	return true, nil
+}
+
+type selfContactDTO struct {
+	Email  string
+	Price  selfCents
+	Amount selfCents
+}
+
+type selfContact struct {
+	Email  selfEmail
+	Price  string
+	Amount int64
+}
+
+type selfAccountDTO struct {
+	Owner    selfContactDTO
+	Contacts []selfContactDTO
+	Aliases  []string
+	ByName   map[string]string
+	Backup   *string
+}
+
+type selfAccount struct {
+	Owner    selfContact
+	Contacts []selfContact
+	Aliases  []selfEmail
+	ByName   map[string]selfEmail
+	Backup   *selfEmail
+}
+
+func TestSelfMapping_NestedTypes(t *testing.T) {
+	backup := "Backup@Example.com"
+	src := selfAccountDTO{
+		Owner:    selfContactDTO{Email: "Owner@Example.com", Price: 250, Amount: 7},
+		Contacts: []selfContactDTO{{Email: "A@Example.com", Price: 100}},
+		Aliases:  []string{"X@Example.com"},
+		ByName:   map[string]string{"b": "B@Example.com"},
+		Backup:   &backup,
+	}
+
+	var dst selfAccount
+	if err := Map(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Owner.Email.addr != "owner@example.com" || dst.Contacts[0].Email.addr != "a@example.com" {
+		t.Errorf("expected MapFrom on nested fields, got %+v", dst)
+	}
+	if dst.Aliases[0].addr != "x@example.com" || dst.ByName["b"].addr != "b@example.com" || dst.Backup.addr != "backup@example.com" {
+		t.Errorf("expected MapFrom on slice elements, map values and pointers, got %+v", dst)
+	}
+	if dst.Owner.Price != "2.5" || dst.Contacts[0].Price != "1" {
+		t.Errorf("expected MapTo on the source, got %q and %q", dst.Owner.Price, dst.Contacts[0].Price)
+	}
+	if dst.Owner.Amount != 7 {
+		t.Errorf("expected unhandled MapTo to fall back to the built-in rules, got %d", dst.Owner.Amount)
+	}
+}
+
+func TestSelfMapping_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*selfAccountDTO)
+		path   string
+	}{
+		{"nested field", func(a *selfAccountDTO) { a.Owner.Email = "x" }, "Owner.Email"},
+		{"slice element field", func(a *selfAccountDTO) { a.Contacts[0].Email = "x" }, "Contacts[0].Email"},
+		{"slice element", func(a *selfAccountDTO) { a.Aliases[1] = "x" }, "Aliases[1]"},
+		{"map value", func(a *selfAccountDTO) { a.ByName["b"] = "x" }, "ByName[b]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := selfAccountDTO{
+				Owner:    selfContactDTO{Email: "o@example.com"},
+				Contacts: []selfContactDTO{{Email: "a@example.com"}},
+				Aliases:  []string{"a@example.com", "b@example.com"},
+				ByName:   map[string]string{"b": "b@example.com"},
+			}
+			tt.mutate(&src)
+
+			var dst selfAccount
+			err := Map(&dst, src)
+
+			var mappingErr *MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("expected *MappingError, got %v", err)
+			}
+			if mappingErr.FieldPath != tt.path || !errors.Is(err, ErrConverterFailed) {
+				t.Errorf("expected converter error at %q, got %v", tt.path, err)
+			}
+			if mappingErr.Reason != "MapFrom failed: invalid email" {
+				t.Errorf("unexpected reason %q", mappingErr.Reason)
+			}
+		})
+	}
+}
+
+func TestSelfMapping_Unhandled(t *testing.T) {
+	type Src struct{ Email int }
+	type Dst struct{ Email selfEmail }
+
+	var dst Dst
+	err := Map(&dst, Src{Email: 1})
+	if !errors.Is(err, ErrIncompatibleTypes) {
+		t.Errorf("expected incompatible types when MapFrom does not handle the value, got %v", err)
+	}
+}
+
+func TestSelfMapping_ConverterTakesPrecedence(t *testing.T) {
+	var dst selfContact
+	err := MapWithOptions(&dst, selfContactDTO{Email: "x"}, WithConverter(func(s string) (selfEmail, error) {
+		return selfEmail{addr: "converted"}, nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Email.addr != "converted" {
+		t.Errorf("expected converter to take precedence, got %q", dst.Email.addr)
+	}
+}
+
+func TestSelfMapOf(t *testing.T) {
+	tests := []struct {
+		typ  reflect.Type
+		want selfMapInfo
+	}{
+		{typeFor[selfEmail](), selfMapInfo{mapFrom: true}},
+		{typeFor[selfCents](), selfMapInfo{mapTo: true}},
+		{typeFor[struct{ selfEmail }](), selfMapInfo{mapFrom: true}},
+		{typeFor[*selfEmail](), selfMapInfo{}},
+		{typeFor[[]selfEmail](), selfMapInfo{}},
+		{typeFor[string](), selfMapInfo{}},
+		{typeFor[MapFromer](), selfMapInfo{}},
+	}
+
+	for _, tt := range tests {
+		if got := selfMapOf(tt.typ); got != tt.want {
+			t.Errorf("selfMapOf(%v) = %+v, want %+v", tt.typ, got, tt.want)
+		}
+	}
+}
+
+type selfCtxKey struct{}
+
+// selfLocalized formats itself for the locale in the context.
+type selfLocalized struct{ text string }
+
+func (l *selfLocalized) MapFrom(src any) (bool, error) {
+	return false, errors.New("MapFrom must not be called")
+}
+
+func (l *selfLocalized) MapFromContext(ctx context.Context, src any) (bool, error) {
+	locale, ok := ctx.Value(selfCtxKey{}).(string)
+	if !ok {
+		return true, errors.New("no locale")
+	}
+	l.text = locale + ":" + src.(string)
+	return true, nil
+}
+
+type selfPrice int
+
+func (p selfPrice) MapToContext(ctx context.Context, dst any) (bool, error) {
+	s, ok := dst.(*string)
+	if !ok {
+		return false, nil
+	}
+	*s = ctx.Value(selfCtxKey{}).(string) + ":" + strconv.Itoa(int(p))
+	return true, nil
+}
+
+func TestSelfMapping_Context(t *testing.T) {
+	type Src struct {
+		Label string
+		Price selfPrice
+	}
+	type Dst struct {
+		Label selfLocalized
+		Price string
+	}
+	ctx := context.WithValue(context.Background(), selfCtxKey{}, "pt")
+
+	var dst Dst
+	if err := MapContext(ctx, &dst, Src{Label: "hello", Price: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Label.text != "pt:hello" || dst.Price != "pt:5" {
+		t.Errorf("expected the context-aware methods to receive the context, got %+v", dst)
+	}
+
+	err := Map(&dst, Src{Label: "hello"})
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) || mappingErr.Reason != "MapFromContext failed: no locale" {
+		t.Errorf("expected MapFromContext to fail without a locale, got %v", err)
+	}
+}
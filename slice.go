@@ -25,14 +25,15 @@ func buildSlicePath(basePath string, index int) string {
 // - empty slices remain empty (not nil)
 // - a new underlying array is created (modifications to source don't affect destination)
 // - element types are converted if compatible
-// - nested structs within slices are properly mapped using the provided tagName
-func assignSlice(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath, tagName string, depth int) error {
+// - nested structs within slices are properly mapped using the configured tag name
+func assignSlice(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
 	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
@@ -49,19 +50,38 @@ func assignSlice(dst, src reflect.Value, srcStructType, dstStructType reflect.Ty
 	length := src.Len()
 	newSlice := reflect.MakeSlice(dType, length, length)
 
-	// Fast path: identical simple element types can use reflect.Copy
-	if srcElemType == dstElemType {
-		elemKind := srcElemType.Kind()
-		if elemKind != reflect.Struct && elemKind != reflect.Slice && elemKind != reflect.Map && elemKind != reflect.Ptr {
-			reflect.Copy(newSlice, src)
+	if cfg.hasConverters() {
+		if conv, ok := cfg.lookupConverter(srcElemType, dstElemType); ok {
+			var errs MappingErrors
+			for i := 0; i < length; i++ {
+				if err := cfg.checkContext(i, srcStructType, dstStructType, fieldPath); err != nil {
+					return cfg.recordError(&errs, err)
+				}
+				if err := applyConverter(conv, newSlice.Index(i), src.Index(i), srcStructType, dstStructType, "", cfg); err != nil {
+					if err = cfg.recordError(&errs, prependIndexPath(err, fieldPath, i)); err != nil {
+						return err
+					}
+				}
+			}
 			dst.Set(newSlice)
-			return nil
+			return errs.orNil()
 		}
 	}
 
 	srcElemKind := srcElemType.Kind()
 	dstElemKind := dstElemType.Kind()
 
+	// Self-mapped and interface elements are assigned one by one, as their
+	// dynamic types decide how
+	elementsNested := selfMapping(srcElemType, dstElemType) || srcElemKind == reflect.Interface || dstElemKind == reflect.Interface
+
+	// Fast path: identical simple element types can use reflect.Copy
+	if srcElemType == dstElemType && !elementsNested && !isCompositeKind(srcElemKind) {
+		reflect.Copy(newSlice, src)
+		dst.Set(newSlice)
+		return nil
+	}
+
 	elementsAreStructs := srcElemKind == reflect.Struct && dstElemKind == reflect.Struct
 	elementsAreSlices := srcElemKind == reflect.Slice && dstElemKind == reflect.Slice
 	elementsAreMaps := srcElemKind == reflect.Map && dstElemKind == reflect.Map
@@ -69,30 +89,63 @@ func assignSlice(dst, src reflect.Value, srcStructType, dstStructType reflect.Ty
 	elementsAssignable := srcElemType.AssignableTo(dstElemType)
 	elementsConvertible := srcElemType.ConvertibleTo(dstElemType)
 
-	if !elementsAssignable && !elementsConvertible && !elementsAreStructs && !elementsAreSlices && !elementsAreMaps && !elementsArePtrs {
+	if !elementsNested && !elementsAssignable && !elementsConvertible && !elementsAreStructs && !elementsAreSlices && !elementsAreMaps && !elementsArePtrs {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "slice element types are incompatible: " + srcElemType.String() + " -> " + dstElemType.String(),
+			Code:      CodeIncompatibleTypes,
 		}
 	}
 
+	// Resolve the element plan once instead of once per element
+	var elemPlan *structPlan
+	if elementsAreStructs && !elementsNested {
+		var err error
+		elemPlan, err = cfg.mapper.getStructPlan(srcElemType, dstElemType, cfg.tagName)
+		if err != nil {
+			return &MappingError{
+				SrcType:   srcStructType.String(),
+				DstType:   dstStructType.String(),
+				FieldPath: fieldPath,
+				Reason:    "failed to get struct metadata: " + err.Error(),
+				Code:      CodeInvalidInput,
+				Err:       err,
+			}
+		}
+	}
+
+	cfg.initElements(newSlice)
+
 	// Slow path: need per-element processing
 	// Pass fieldPath and index separately; path with index is only built on error
+	var errs MappingErrors
 	for i := 0; i < length; i++ {
+		if err := cfg.checkContext(i, srcStructType, dstStructType, fieldPath); err != nil {
+			return cfg.recordError(&errs, err)
+		}
+
 		srcElem := src.Index(i)
 		dstElem := newSlice.Index(i)
 
 		var err error
-		if elementsAreStructs {
-			err = assignStructWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, tagName, depth-1)
+		if elementsNested {
+			if err = assignNestedValue(dstElem, srcElem, srcStructType, dstStructType, "", "", "", cfg, depth-1); err != nil {
+				err = prependIndexPath(err, fieldPath, i)
+			}
+		} else if elementsAreStructs {
+			err = assignStructWithIndex(dstElem, srcElem, elemPlan, srcStructType, dstStructType, fieldPath, i, cfg, depth-1)
 		} else if elementsAreSlices {
-			err = assignSliceWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, tagName, depth-1)
+			err = assignSliceWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, cfg, depth-1)
 		} else if elementsAreMaps {
-			err = assignMapWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, tagName, depth-1)
+			err = assignMapWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, cfg, depth-1)
 		} else if elementsArePtrs {
-			err = assignPointerElementWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, tagName, depth-1)
+			if cfg.pointers == pointerReuse && i < dst.Len() {
+				// Map into the pointee at the same index of the destination
+				dstElem.Set(dst.Index(i))
+			}
+			err = assignPointerElementWithIndex(dstElem, srcElem, srcStructType, dstStructType, fieldPath, i, cfg, depth-1)
 		} else if elementsAssignable {
 			dstElem.Set(srcElem)
 		} else if elementsConvertible {
@@ -100,32 +153,35 @@ func assignSlice(dst, src reflect.Value, srcStructType, dstStructType reflect.Ty
 		}
 
 		if err != nil {
-			return err
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
 
 	dst.Set(newSlice)
-	return nil
+	return errs.orNil()
 }
 
 // prependIndexPath prepends the slice index path to a MappingError's FieldPath.
 // This is called only when an error occurs, making path building lazy.
 func prependIndexPath(err error, basePath string, index int) error {
-	if me, ok := err.(*MappingError); ok {
+	switch e := err.(type) {
+	case *MappingError:
+		e.FieldPath = joinPath(buildSlicePath(basePath, index), e.FieldPath)
+	case MappingErrors:
 		indexPath := buildSlicePath(basePath, index)
-		if me.FieldPath != "" {
-			me.FieldPath = indexPath + "." + me.FieldPath
-		} else {
-			me.FieldPath = indexPath
+		for _, me := range e {
+			me.FieldPath = joinPath(indexPath, me.FieldPath)
 		}
 	}
 	return err
 }
 
 // assignStructWithIndex is a wrapper that builds the path with index only when an error occurs.
-func assignStructWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, tagName string, depth int) error {
+func assignStructWithIndex(dst, src reflect.Value, plan *structPlan, srcStructType, dstStructType reflect.Type, basePath string, index int, cfg *config, depth int) error {
 	// Pass empty path to avoid allocation; path is built only on error
-	err := assignStruct(dst, src, srcStructType, dstStructType, "", tagName, depth)
+	err := assignStructPlan(dst, src, plan, srcStructType, dstStructType, "", cfg, depth)
 	if err != nil {
 		return prependIndexPath(err, basePath, index)
 	}
@@ -133,9 +189,9 @@ func assignStructWithIndex(dst, src reflect.Value, srcStructType, dstStructType
 }
 
 // assignSliceWithIndex is a wrapper that builds the path with index only when an error occurs.
-func assignSliceWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, tagName string, depth int) error {
+func assignSliceWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, cfg *config, depth int) error {
 	// Pass empty path to avoid allocation; path is built only on error
-	err := assignSlice(dst, src, srcStructType, dstStructType, "", tagName, depth)
+	err := assignSlice(dst, src, srcStructType, dstStructType, "", cfg, depth)
 	if err != nil {
 		return prependIndexPath(err, basePath, index)
 	}
@@ -143,9 +199,9 @@ func assignSliceWithIndex(dst, src reflect.Value, srcStructType, dstStructType r
 }
 
 // assignMapWithIndex is a wrapper that builds the path with index only when an error occurs.
-func assignMapWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, tagName string, depth int) error {
+func assignMapWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, cfg *config, depth int) error {
 	// Pass empty path to avoid allocation; path is built only on error
-	err := assignMap(dst, src, srcStructType, dstStructType, "", tagName, depth)
+	err := assignMap(dst, src, srcStructType, dstStructType, "", cfg, depth)
 	if err != nil {
 		return prependIndexPath(err, basePath, index)
 	}
@@ -153,9 +209,9 @@ func assignMapWithIndex(dst, src reflect.Value, srcStructType, dstStructType ref
 }
 
 // assignPointerElementWithIndex is a wrapper that builds the path with index only when an error occurs.
-func assignPointerElementWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, tagName string, depth int) error {
+func assignPointerElementWithIndex(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath string, index int, cfg *config, depth int) error {
 	// Pass empty path to avoid allocation; path is built only on error
-	err := assignPointerElement(dst, src, srcStructType, dstStructType, "", tagName, depth)
+	err := assignPointerElement(dst, src, srcStructType, dstStructType, "", cfg, depth)
 	if err != nil {
 		return prependIndexPath(err, basePath, index)
 	}
@@ -163,13 +219,14 @@ func assignPointerElementWithIndex(dst, src reflect.Value, srcStructType, dstStr
 }
 
 // assignPointerElement handles pointer elements within slices and maps.
-func assignPointerElement(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath, tagName string, depth int) error {
+func assignPointerElement(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
 	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
@@ -177,29 +234,58 @@ func assignPointerElement(dst, src reflect.Value, srcStructType, dstStructType r
 		dst.Set(reflect.Zero(dst.Type()))
 		return nil
 	}
+	if ref, ok := cfg.mappedRef(src, dst.Type()); ok {
+		dst.Set(ref)
+		return nil
+	}
 
 	srcElem := src.Elem()
 	dstElemType := dst.Type().Elem()
 
-	newPtr := reflect.New(dstElemType)
+	newPtr := newPointee(dst, cfg)
+	cfg.rememberRef(src, newPtr)
+
+	if cfg.hasConverters() {
+		if conv, ok := cfg.lookupConverter(srcElem.Type(), dstElemType); ok {
+			if err := applyConverter(conv, newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, cfg); err != nil {
+				return err
+			}
+			dst.Set(newPtr)
+			return nil
+		}
+	}
+
+	if selfMapping(srcElem.Type(), dstElemType) {
+		if handled, err := assignSelf(cfg.context(), newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath); handled {
+			if err != nil {
+				return err
+			}
+			dst.Set(newPtr)
+			return nil
+		}
+	}
 
 	srcElemKind := srcElem.Kind()
 	dstElemKind := dstElemType.Kind()
 
-	if srcElemKind == reflect.Struct && dstElemKind == reflect.Struct {
-		if err := assignStruct(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, tagName, depth-1); err != nil {
+	if srcElemKind == reflect.Interface || dstElemKind == reflect.Interface {
+		if err := assignNestedValue(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, "", "", cfg, depth-1); err != nil {
+			return err
+		}
+	} else if srcElemKind == reflect.Struct && dstElemKind == reflect.Struct {
+		if err := assignStruct(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, cfg, depth-1); err != nil {
 			return err
 		}
 	} else if srcElemKind == reflect.Slice && dstElemKind == reflect.Slice {
-		if err := assignSlice(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, tagName, depth-1); err != nil {
+		if err := assignSlice(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, cfg, depth-1); err != nil {
 			return err
 		}
 	} else if srcElemKind == reflect.Map && dstElemKind == reflect.Map {
-		if err := assignMap(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, tagName, depth-1); err != nil {
+		if err := assignMap(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, cfg, depth-1); err != nil {
 			return err
 		}
 	} else if srcElemKind == reflect.Ptr && dstElemKind == reflect.Ptr {
-		if err := assignPointerElement(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, tagName, depth-1); err != nil {
+		if err := assignPointerElement(newPtr.Elem(), srcElem, srcStructType, dstStructType, fieldPath, cfg, depth-1); err != nil {
 			return err
 		}
 	} else if srcElem.Type().AssignableTo(dstElemType) {
@@ -212,6 +298,7 @@ func assignPointerElement(dst, src reflect.Value, srcStructType, dstStructType r
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "incompatible pointer element types: " + srcElem.Type().String() + " -> " + dstElemType.String(),
+			Code:      CodeIncompatibleTypes,
 		}
 	}
 
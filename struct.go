@@ -9,66 +9,138 @@ import (
 // - struct fields are mapped by name or tag
 // - nested structs are recursively processed
 // - a new struct is created (deep copy behavior)
-func assignStruct(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath, tagName string, depth int) error {
+func assignStruct(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
 	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
 			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
-	srcType := src.Type()
-	dstType := dst.Type()
-
-	srcMeta, err := getStructMeta(srcType, tagName)
+	plan, err := cfg.mapper.getStructPlan(src.Type(), dst.Type(), cfg.tagName)
 	if err != nil {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
-			Reason:    "failed to get source struct metadata: " + err.Error(),
+			Reason:    "failed to get struct metadata: " + err.Error(),
+			Code:      CodeInvalidInput,
+			Err:       err,
 		}
 	}
 
-	if srcType == dstType && !srcMeta.HasComposite {
-		dst.Set(src)
-		return nil
-	}
+	return assignStructPlan(dst, src, plan, srcStructType, dstStructType, fieldPath, cfg, depth)
+}
 
-	dstMeta, err := getStructMeta(dstType, tagName)
-	if err != nil {
+// assignStructPlan maps src into dst using an already resolved plan.
+// Collections of structs resolve the plan once and call this per element.
+func assignStructPlan(dst, src reflect.Value, plan *structPlan, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, depth int) error {
+	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: fieldPath,
-			Reason:    "failed to get destination struct metadata: " + err.Error(),
+			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
-	// Iterate over destination fields slice (better cache locality than map iteration)
-	for _, dstFieldMeta := range dstMeta.Fields {
-		dstName := dstFieldMeta.Name
-		srcFieldMeta, ok := srcMeta.FieldsByName[dstName]
+	// Converters may target field types, patch semantics skip zero fields and
+	// change reports list every field, so identical structs are only copied in
+	// one step when none of them applies.
+	if plan.copyWhole && !cfg.hasConverters() && !cfg.ignoreZeroSource && cfg.report == nil {
+		dst.Set(src)
+		return nil
+	}
 
-		if !ok {
-			srcFieldMeta, ok = srcMeta.FieldsByTag[dstName]
+	if plan.hooks != 0 {
+		if err := runBeforeHooks(cfg.context(), plan, dst, src, srcStructType, dstStructType, fieldPath); err != nil {
+			return err
 		}
+	}
 
-		if !ok {
+	var errs MappingErrors
+	for i := range plan.fields {
+		fp := &plan.fields[i]
+		if fp.unmatched {
+			if cfg.isStrict(plan.dstType) {
+				err := &MappingError{
+					SrcType:   srcStructType.String(),
+					DstType:   dstStructType.String(),
+					FieldPath: buildPath(fieldPath, fp.name),
+					Reason:    "no matching source field found",
+					Code:      CodeUnmatchedField,
+				}
+				if err := cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+			}
+			if cfg.report != nil {
+				reportSkipped(FieldUnmatched, fp, dst.Field(fp.dstIndex), fieldPath, cfg)
+			}
 			continue
 		}
 
-		srcField := src.FieldByIndex(srcFieldMeta.Index)
-		dstField := dst.FieldByIndex(dstFieldMeta.Index)
+		srcField := src.Field(fp.srcIndex)
+		if cfg.ignoreZeroSource && srcField.IsZero() {
+			if cfg.report != nil {
+				reportSkipped(FieldSkippedZero, fp, dst.Field(fp.dstIndex), fieldPath, cfg)
+			}
+			continue
+		}
+
+		if cfg.report != nil {
+			if err := reportAssign(fp, dst.Field(fp.dstIndex), srcField, srcStructType, dstStructType, fieldPath, cfg, depth); err != nil {
+				if err = cfg.recordError(&errs, err); err != nil {
+					return err
+				}
+			}
+			continue
+		}
 
 		// Pass base path and field name separately; path is only built on error
-		if err := assignNestedValue(dstField, srcField, srcStructType, dstStructType, fieldPath, dstName, tagName, srcFieldMeta.ConvertTo, depth); err != nil {
+		if err := assignField(fp, dst.Field(fp.dstIndex), srcField, srcStructType, dstStructType, fieldPath, cfg, depth); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.strictSource {
+		if err := reportUnconsumed(plan, srcStructType, dstStructType, fieldPath, cfg, &errs); err != nil {
 			return err
 		}
 	}
 
+	if plan.hooks != 0 {
+		if err := runAfterHooks(cfg.context(), plan, dst, src, srcStructType, dstStructType, fieldPath); err != nil {
+			if err = cfg.recordError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return errs.orNil()
+}
+
+// reportUnconsumed records an error for every source field of plan that no
+// destination field maps from. It returns a non-nil error when mapping must stop.
+func reportUnconsumed(plan *structPlan, srcStructType, dstStructType reflect.Type, fieldPath string, cfg *config, errs *MappingErrors) error {
+	for _, name := range plan.unconsumed {
+		err := &MappingError{
+			SrcType:   srcStructType.String(),
+			DstType:   dstStructType.String(),
+			FieldPath: buildPath(fieldPath, name),
+			Reason:    "source field is not mapped to any destination field",
+			Code:      CodeUnmappedSource,
+		}
+		if err := cfg.recordError(errs, err); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -78,20 +150,54 @@ func buildPath(basePath, fieldName string) string {
 	if basePath == "" {
 		return fieldName
 	}
+	if fieldName == "" {
+		return basePath
+	}
 	return basePath + "." + fieldName
 }
 
+// joinPath prepends prefix to a relative field path. Relative paths that start
+// with an index or key ("[1].Name") are appended without a separating dot.
+func joinPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	if path[0] == '[' {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
+
+// newPointee returns the pointer a pointer destination is mapped through.
+// An existing pointee is reused with WithReusePointers, and a struct pointee
+// with patch semantics so its fields are merged; otherwise a new value is
+// allocated, starting from its factory if there is one.
+func newPointee(dst reflect.Value, cfg *config) reflect.Value {
+	if !dst.IsNil() {
+		switch cfg.pointers {
+		case pointerReuse:
+			return dst
+		case pointerDefault:
+			if cfg.ignoreZeroSource && dst.Type().Elem().Kind() == reflect.Struct {
+				return dst
+			}
+		}
+	}
+	return cfg.newPointer(dst.Type())
+}
+
 // assignNestedValue handles value assignment within nested contexts (structs, slices, maps).
 // It supports nested structs, slices, maps, pointers, and type conversions.
 // basePath and fieldName are kept separate to avoid string concatenation in the hot path;
 // the full path is only built when an error occurs.
-func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath, fieldName, tagName, convertTo string, depth int) error {
+func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType reflect.Type, basePath, fieldName, convertTo string, cfg *config, depth int) error {
 	if depth <= 0 {
 		return &MappingError{
 			SrcType:   srcStructType.String(),
 			DstType:   dstStructType.String(),
 			FieldPath: buildPath(basePath, fieldName),
 			Reason:    "maximum nesting depth exceeded (possible circular reference)",
+			Code:      CodeDepthExceeded,
 		}
 	}
 
@@ -101,6 +207,7 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 			DstType:   dstStructType.String(),
 			FieldPath: buildPath(basePath, fieldName),
 			Reason:    "destination field cannot be set",
+			Code:      CodeFieldNotSettable,
 		}
 	}
 
@@ -117,13 +224,39 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 		return nil
 	}
 
+	if cfg.hasConverters() {
+		if conv, ok := cfg.lookupConverter(sType, dType); ok {
+			return applyConverter(conv, dst, src, srcStructType, dstStructType, buildPath(basePath, fieldName), cfg)
+		}
+	}
+
 	srcKind := sType.Kind()
 	dstKind := dType.Kind()
 
+	// Interface sources are mapped from their dynamic value, which may have
+	// converters, MapFrom or an implementation of its own
+	if srcKind == reflect.Interface {
+		if src.IsNil() {
+			// A nil interface holds no value, so the destination gets none either
+			dst.Set(reflect.Zero(dType))
+			return nil
+		}
+		return assignNestedValue(dst, src.Elem(), srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth-1)
+	}
+
+	if selfMapping(sType, dType) {
+		if handled, err := assignSelf(cfg.context(), dst, src, srcStructType, dstStructType, buildPath(basePath, fieldName)); handled {
+			return err
+		}
+	}
+
+	if dstKind == reflect.Interface {
+		return assignInterface(dst, src, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth)
+	}
+
 	// Fast path: directly assignable or convertible types (most common for primitive fields)
 	// Check these first to avoid path building for the majority of field assignments
-	if srcKind != reflect.Struct && srcKind != reflect.Slice && srcKind != reflect.Map && srcKind != reflect.Ptr &&
-		dstKind != reflect.Struct && dstKind != reflect.Slice && dstKind != reflect.Map && dstKind != reflect.Ptr {
+	if !isCompositeKind(srcKind) && !isCompositeKind(dstKind) {
 		if sType.AssignableTo(dType) {
 			dst.Set(src)
 			return nil
@@ -137,6 +270,17 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 			DstType:   dstStructType.String(),
 			FieldPath: buildPath(basePath, fieldName),
 			Reason:    "incompatible field types: " + sType.String() + " -> " + dType.String(),
+			Code:      CodeIncompatibleTypes,
+		}
+	}
+
+	if srcKind == reflect.Struct {
+		if info := optionalOf(sType); info != nil {
+			if sType.AssignableTo(dType) {
+				dst.Set(src)
+				return nil
+			}
+			return assignOptional(dst, src, info, srcStructType, dstStructType, basePath, fieldName, convertTo, cfg, depth)
 		}
 	}
 
@@ -144,15 +288,15 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 	fullPath := buildPath(basePath, fieldName)
 
 	if srcKind == reflect.Struct && dstKind == reflect.Struct {
-		return assignStruct(dst, src, srcStructType, dstStructType, fullPath, tagName, depth-1)
+		return assignStruct(dst, src, srcStructType, dstStructType, fullPath, cfg, depth-1)
 	}
 
 	if srcKind == reflect.Slice && dstKind == reflect.Slice {
-		return assignSlice(dst, src, srcStructType, dstStructType, fullPath, tagName, depth-1)
+		return assignSlice(dst, src, srcStructType, dstStructType, fullPath, cfg, depth-1)
 	}
 
 	if srcKind == reflect.Map && dstKind == reflect.Map {
-		return assignMap(dst, src, srcStructType, dstStructType, fullPath, tagName, depth-1)
+		return assignMap(dst, src, srcStructType, dstStructType, fullPath, cfg, depth-1)
 	}
 
 	if srcKind == reflect.Ptr && dstKind == reflect.Ptr {
@@ -160,8 +304,23 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 			dst.Set(reflect.Zero(dType))
 			return nil
 		}
-		newPtr := reflect.New(dType.Elem())
-		if err := assignNestedValue(newPtr.Elem(), src.Elem(), srcStructType, dstStructType, fullPath, "", tagName, convertTo, depth-1); err != nil {
+		if ref, ok := cfg.mappedRef(src, dType); ok {
+			dst.Set(ref)
+			return nil
+		}
+
+		// Identical simple element types can be copied without recursion
+		if elemType := dType.Elem(); sType.Elem() == elemType && !isCompositeKind(elemType.Kind()) && convertTo == "" && !cfg.hasConverters() && !selfMapping(elemType, elemType) {
+			newPtr := newPointee(dst, cfg)
+			newPtr.Elem().Set(src.Elem())
+			cfg.rememberRef(src, newPtr)
+			dst.Set(newPtr)
+			return nil
+		}
+
+		newPtr := newPointee(dst, cfg)
+		cfg.rememberRef(src, newPtr)
+		if err := assignNestedValue(newPtr.Elem(), src.Elem(), srcStructType, dstStructType, fullPath, "", convertTo, cfg, depth-1); err != nil {
 			return err
 		}
 		dst.Set(newPtr)
@@ -172,12 +331,12 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 		if src.IsNil() {
 			return nil
 		}
-		return assignNestedValue(dst, src.Elem(), srcStructType, dstStructType, fullPath, "", tagName, convertTo, depth-1)
+		return assignNestedValue(dst, src.Elem(), srcStructType, dstStructType, fullPath, "", convertTo, cfg, depth-1)
 	}
 
 	if srcKind != reflect.Ptr && dstKind == reflect.Ptr {
-		newPtr := reflect.New(dType.Elem())
-		if err := assignNestedValue(newPtr.Elem(), src, srcStructType, dstStructType, fullPath, "", tagName, convertTo, depth-1); err != nil {
+		newPtr := newPointee(dst, cfg)
+		if err := assignNestedValue(newPtr.Elem(), src, srcStructType, dstStructType, fullPath, "", convertTo, cfg, depth-1); err != nil {
 			return err
 		}
 		dst.Set(newPtr)
@@ -199,5 +358,6 @@ func assignNestedValue(dst, src reflect.Value, srcStructType, dstStructType refl
 		DstType:   dstStructType.String(),
 		FieldPath: fullPath,
 		Reason:    "incompatible field types: " + sType.String() + " -> " + dType.String(),
+		Code:      CodeIncompatibleTypes,
 	}
 }
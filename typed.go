@@ -0,0 +1,86 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// To maps src into a new value of type D and returns it.
+//
+// D must be a struct type or a pointer to a struct type; for a pointer type a
+// new struct is allocated. The src argument follows the same rules as in [Map].
+//
+// Example:
+//
+//	user, err := mapper.To[User](dto)
+//	userPtr, err := mapper.To[*User](dto, mapper.WithStrictMode())
+//
+// On failure To returns the zero value of D and a [*MappingError].
+func To[D any](src any, opts ...Option) (D, error) {
+	var dst D
+	cfg := defaultMapper.callConfig(opts)
+
+	dstVal := reflect.ValueOf(&dst).Elem()
+	target := any(&dst)
+	if dstVal.Kind() == reflect.Ptr && dstVal.Type().Elem().Kind() == reflect.Struct {
+		dstVal.Set(cfg.newPointer(dstVal.Type()))
+		target = dstVal.Interface()
+	} else {
+		dstVal.Set(cfg.newValue(dstVal.Type()))
+	}
+
+	if err := runMapping(target, src, &cfg); err != nil {
+		var zero D
+		return zero, err
+	}
+	return dst, nil
+}
+
+// MapSlice maps every element of src into a new slice of D.
+//
+// Elements are mapped with the same rules as slice fields: struct elements are
+// mapped field by field, and other elements are copied, converted or passed
+// to a registered converter. A nil src yields a nil slice and an empty src an
+// empty slice.
+//
+// Example:
+//
+//	dtos, err := mapper.MapSlice[UserDTO](users)
+//
+// Element errors are returned as a [*MappingError] whose FieldPath starts with
+// the element index, for example "[3].Name".
+func MapSlice[D, S any](src []S, opts ...Option) ([]D, error) {
+	cfg := defaultMapper.callConfig(opts)
+
+	var dst []D
+	dstVal := reflect.ValueOf(&dst).Elem()
+	srcVal := reflect.ValueOf(src)
+
+	if err := assignSlice(dstVal, srcVal, srcVal.Type(), dstVal.Type(), "", &cfg, cfg.maxDepth); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// MapMap maps every value of src into a new map of D with the same keys.
+//
+// Values are mapped with the same rules as map fields. A nil src yields a nil
+// map and an empty src an empty map.
+//
+// Example:
+//
+//	byID, err := mapper.MapMap[string, UserDTO](usersByID)
+//
+// Value errors are returned as a [*MappingError] whose FieldPath starts with
+// the key, for example "[alice].Name".
+func MapMap[K comparable, D, S any](src map[K]S, opts ...Option) (map[K]D, error) {
+	cfg := defaultMapper.callConfig(opts)
+
+	var dst map[K]D
+	dstVal := reflect.ValueOf(&dst).Elem()
+	srcVal := reflect.ValueOf(src)
+
+	if err := assignMap(dstVal, srcVal, srcVal.Type(), dstVal.Type(), "", &cfg, cfg.maxDepth); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -0,0 +1,159 @@
+package mapper
+
+import (
+	"testing"
+)
+
+type typedSrc struct {
+	Name string
+	Age  string `mapconv:"int"`
+}
+
+type typedDst struct {
+	Name string
+	Age  int
+}
+
+func TestTo(t *testing.T) {
+	dst, err := To[typedDst](typedSrc{Name: "Alice", Age: "30"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "Alice" || dst.Age != 30 {
+		t.Errorf("unexpected result %+v", dst)
+	}
+
+	ptr, err := To[*typedDst](&typedSrc{Name: "Bob", Age: "40"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr == nil || ptr.Name != "Bob" || ptr.Age != 40 {
+		t.Errorf("unexpected result %+v", ptr)
+	}
+}
+
+func TestTo_Errors(t *testing.T) {
+	dst, err := To[typedDst](typedSrc{Name: "Alice", Age: "abc"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if mappingErr, ok := err.(*MappingError); !ok || mappingErr.FieldPath != "Age" {
+		t.Errorf("expected *MappingError for Age, got %v", err)
+	}
+	if dst != (typedDst{}) {
+		t.Errorf("expected zero value on error, got %+v", dst)
+	}
+
+	if _, err := To[int](typedSrc{}); err == nil {
+		t.Error("expected error for non-struct destination, got nil")
+	}
+
+	type Dst struct {
+		Name  string
+		Email string
+	}
+	if _, err := To[Dst](typedSrc{}, WithStrictMode()); err == nil {
+		t.Error("expected strict mode error, got nil")
+	}
+}
+
+func TestMapSlice(t *testing.T) {
+	src := []typedSrc{{Name: "Alice", Age: "30"}, {Name: "Bob", Age: "40"}}
+
+	dst, err := MapSlice[typedDst](src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst) != 2 || dst[0].Name != "Alice" || dst[1].Age != 40 {
+		t.Errorf("unexpected result %+v", dst)
+	}
+
+	ints, err := MapSlice[int64]([]int32{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ints) != 3 || ints[2] != 3 {
+		t.Errorf("unexpected result %v", ints)
+	}
+}
+
+func TestMapSlice_NilAndEmpty(t *testing.T) {
+	dst, err := MapSlice[typedDst]([]typedSrc(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst != nil {
+		t.Errorf("expected nil slice, got %v", dst)
+	}
+
+	dst, err = MapSlice[typedDst]([]typedSrc{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst == nil || len(dst) != 0 {
+		t.Errorf("expected empty slice, got %v", dst)
+	}
+}
+
+func TestMapSlice_ErrorPath(t *testing.T) {
+	src := []typedSrc{{Age: "1"}, {Age: "2"}, {Age: "3"}, {Age: "x"}}
+
+	dst, err := MapSlice[typedDst](src)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T", err)
+	}
+	if mappingErr.FieldPath != "[3].Age" {
+		t.Errorf("expected FieldPath '[3].Age', got %q", mappingErr.FieldPath)
+	}
+	if dst != nil {
+		t.Errorf("expected nil result on error, got %v", dst)
+	}
+}
+
+func TestMapSlice_IncompatibleElements(t *testing.T) {
+	_, err := MapSlice[typedDst]([]string{"a"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, ok := err.(*MappingError); !ok {
+		t.Errorf("expected *MappingError, got %T", err)
+	}
+}
+
+func TestMapMap(t *testing.T) {
+	src := map[string]typedSrc{"a": {Name: "Alice", Age: "30"}}
+
+	dst, err := MapMap[string, typedDst](src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst["a"].Name != "Alice" || dst["a"].Age != 30 {
+		t.Errorf("unexpected result %+v", dst)
+	}
+
+	nilDst, err := MapMap[string, typedDst](map[string]typedSrc(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nilDst != nil {
+		t.Errorf("expected nil map, got %v", nilDst)
+	}
+}
+
+func TestMapMap_ErrorPath(t *testing.T) {
+	_, err := MapMap[string, typedDst](map[string]typedSrc{"bob": {Age: "x"}})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	mappingErr, ok := err.(*MappingError)
+	if !ok {
+		t.Fatalf("expected *MappingError, got %T", err)
+	}
+	if mappingErr.FieldPath != "[bob].Age" {
+		t.Errorf("expected FieldPath '[bob].Age', got %q", mappingErr.FieldPath)
+	}
+}